@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker/dockertest"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
+)
+
+// useFakeHost points the package's cfg and mgr at a fake Docker host
+// running the given runner numbers of c, restoring them when the test ends.
+func useFakeHost(t *testing.T, c *config.Config, nums ...int) *dockertest.Fake {
+	t.Helper()
+	f := dockertest.New()
+	for _, n := range nums {
+		f.Add(docker.RunnerContainer{Name: fmt.Sprintf("%s-runner-%d", c.Runners.NamePrefix, n), Num: n, Labels: docker.ManagedLabels(c, n)})
+	}
+	oldCfg, oldMgr := cfg, mgr
+	cfg, mgr = c, runner.NewManager(c, []*runner.Host{{Runtime: f}})
+	t.Cleanup(func() { cfg, mgr = oldCfg, oldMgr })
+	return f
+}
+
+// testOrgConfig returns a config for the org "myorg".
+func testOrgConfig() *config.Config {
+	c := config.Default()
+	c.Org = "myorg"
+	c.Token = "ghp_test"
+	return c
+}
+
+// captureStdout returns what fn writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	defer func() { os.Stdout = old }()
+	fn()
+	w.Close()
+	return <-done
+}
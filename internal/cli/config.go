@@ -0,0 +1,400 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"gopkg.in/yaml.v3"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and edit configuration",
+		Long: `View and edit ghr configuration.
+
+Keys are dotted paths such as runners.image or docker.restart_policy.
+set and edit modify the highest-priority config file (or --file) in place,
+keeping comments and key order, and refuse to save a config that does not
+validate.`,
+	}
+
+	cmd.AddCommand(
+		newConfigGetCmd(),
+		newConfigSetCmd(),
+		newConfigViewCmd(),
+		newConfigEditCmd(),
+		newConfigValidateCmd(),
+		newConfigMigrateCmd(),
+		newConfigSchemaCmd(),
+	)
+	return cmd
+}
+
+func newConfigGetCmd() *cobra.Command {
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "get KEY",
+		Short: "Print a config value",
+		Long: `Print a config value. The token is redacted as in ` + "`config view`" + `
+unless --show-secrets is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := loadConfig(cfgFile)
+			if err != nil {
+				return err
+			}
+			view := *loaded.Config
+			if !showSecrets {
+				view.Token = config.RedactToken(view.Token)
+			}
+			v, ok := config.Lookup(&view, args[0])
+			if !ok {
+				return fmt.Errorf("unknown config key %q", args[0])
+			}
+			switch v := v.(type) {
+			case string, int, bool:
+				fmt.Println(v)
+				return nil
+			}
+			data, err := yaml.Marshal(v)
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(data))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "print the token in clear text")
+	return cmd
+}
+
+func newConfigSetCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set a config value",
+		Long: `Set a config value in the config file. VALUE is parsed according to the
+key's type: lists are comma-separated (a,b,c) and maps are KEY=VALUE pairs.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, raw := args[0], args[1]
+			loaded, err := loadConfig(cfgFile)
+			if err != nil {
+				return err
+			}
+			target := loaded.Path
+			if file != "" {
+				target = file
+			}
+
+			value, err := config.ParseValue(key, raw)
+			if err != nil {
+				return err
+			}
+			doc, err := config.OpenDocument(target)
+			if err != nil {
+				return err
+			}
+			if err := doc.Set(key, value); err != nil {
+				return err
+			}
+			data, err := doc.Bytes()
+			if err != nil {
+				return err
+			}
+			if err := checkConfigEdit(loaded, target, data); err != nil {
+				return fmt.Errorf("not saved: %w", err)
+			}
+			if err := config.WriteFile(target, data); err != nil {
+				return err
+			}
+			fmt.Printf("Set %s in %s\n", key, target)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "config file to modify (default: the highest-priority config file)")
+	return cmd
+}
+
+func newConfigViewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Print the merged config with the token redacted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := loadConfig(cfgFile)
+			if err != nil {
+				return err
+			}
+			view := *loaded.Config
+			view.Token = config.RedactToken(view.Token)
+			data, err := yaml.Marshal(&view)
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(data))
+			return nil
+		},
+	}
+}
+
+func newConfigEditCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the config file in $EDITOR",
+		Long: `Open the config file in $VISUAL or $EDITOR (default: vi). The edited file
+is validated before it is saved; if it is invalid you can re-open the
+editor or discard the changes.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			loaded, err := loadConfig(cfgFile)
+			if err != nil {
+				return err
+			}
+			target := loaded.Path
+			if file != "" {
+				target = file
+			}
+
+			original, err := os.ReadFile(target)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("reading config: %w", err)
+			}
+
+			edited := original
+			reader := bufio.NewReader(os.Stdin)
+			for {
+				edited, err = editInEditor(target, edited)
+				if err != nil {
+					return err
+				}
+				if bytes.Equal(edited, original) {
+					fmt.Println("No changes.")
+					return nil
+				}
+				err = checkConfigEdit(loaded, target, edited)
+				if err == nil {
+					break
+				}
+				fmt.Printf("Invalid config: %v\n", err)
+				fmt.Print("Re-open editor? [Y/n] ")
+				if answer := strings.ToLower(readLine(reader)); answer != "" && answer != "y" {
+					fmt.Println("Changes discarded.")
+					return nil
+				}
+			}
+
+			if err := config.WriteFile(target, edited); err != nil {
+				return err
+			}
+			fmt.Printf("Config written to %s\n", target)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "config file to edit (default: the highest-priority config file)")
+	return cmd
+}
+
+func newConfigValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [PATH]",
+		Short: "Validate a config file or the merged config",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := cfgFile
+			if len(args) == 1 {
+				path = args[0]
+			}
+			loaded, err := loadConfig(path)
+			if err != nil {
+				return err
+			}
+			if err := loaded.Validate(); err != nil {
+				return fmt.Errorf("invalid config: %w", err)
+			}
+			fmt.Printf("%s: OK\n", strings.Join(loaded.Files, ", "))
+			for _, f := range loaded.Outdated {
+				fmt.Printf("Note: %s uses an older config format; run `ghr config migrate` to upgrade it.\n", f)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigMigrateCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade config files to the current format",
+		Long: `Upgrade every discovered config file (or the --config file) to the current
+config format version. With a context selected, its config file is upgraded
+instead. The changes are shown as a diff, and the original
+file is kept next to it as <file>.v<N>.bak.
+
+Older files keep working without migrating, since they are upgraded in memory
+when loaded. ` + "`ghr doctor`" + ` and ` + "`ghr config validate`" + ` point out files that
+are not migrated yet.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := cfgFile
+			if path == "" {
+				c, err := activeContext()
+				if err != nil {
+					return err
+				}
+				if c != nil {
+					path = c.Config
+				}
+			}
+			files := []string{path}
+			if path == "" {
+				files = config.SearchPaths()
+				if len(files) == 0 {
+					return fmt.Errorf("no config file found; run `ghr init` to create one")
+				}
+			}
+			for _, path := range files {
+				if err := migrateConfigFile(path, dryRun); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the changes without writing them")
+	return cmd
+}
+
+func migrateConfigFile(path string, dryRun bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	doc, err := config.ParseDocument(path, original)
+	if err != nil {
+		return err
+	}
+	from, err := doc.Version()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	applied, err := doc.Migrate()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Printf("%s: up to date (version %d)\n", path, from)
+		return nil
+	}
+	migrated, err := doc.Bytes()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: version %d -> %d\n", path, from, config.CurrentVersion)
+	for _, m := range applied {
+		fmt.Printf("  %d -> %d: %s\n", m.Version, m.Version+1, m.Description)
+	}
+	fmt.Println()
+	output.PrintDiff(os.Stdout, path, path, original, migrated)
+	fmt.Println()
+	if dryRun {
+		return nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	if err := os.WriteFile(backup, original, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	if err := config.WriteFile(path, migrated); err != nil {
+		return err
+	}
+	fmt.Printf("Migrated %s (backup: %s)\n", path, backup)
+	return nil
+}
+
+func newConfigSchemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for config files",
+		Long: `Print a JSON Schema describing config.yaml, for editor completion and
+validation. For editors using yaml-language-server, save it and reference it
+from the top of the config file:
+
+  ghr config schema > ~/.ghr/config.schema.json
+  # yaml-language-server: $schema=config.schema.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := json.MarshalIndent(config.Schema(), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+}
+
+// checkConfigEdit validates the merged config that would result from
+// writing data to path.
+func checkConfigEdit(loaded *config.Loaded, path string, data []byte) error {
+	check, err := loaded.WithFile(path, data)
+	if err != nil {
+		return err
+	}
+	return check.Validate()
+}
+
+// editInEditor writes content to a temporary file, opens it in the user's
+// editor and returns the edited content.
+func editInEditor(path string, content []byte) ([]byte, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "ghr-*-"+filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("writing temp file: %w", err)
+	}
+	tmp.Close()
+
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], tmp.Name())...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return os.ReadFile(tmp.Name())
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigGetRedactsToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("scope: org\norg: myorg\ntoken: ghp_secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	oldFile := cfgFile
+	cfgFile = path
+	t.Cleanup(func() { cfgFile = oldFile })
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"token"}, "********"},
+		{[]string{"token", "--show-secrets"}, "ghp_secret"},
+		{[]string{"org"}, "myorg"},
+	}
+	for _, tt := range tests {
+		cmd := newConfigGetCmd()
+		cmd.SetArgs(tt.args)
+		var err error
+		out := captureStdout(t, func() { err = cmd.Execute() })
+		if err != nil {
+			t.Fatalf("config get %v error = %v", tt.args, err)
+		}
+		if got := strings.TrimSpace(out); got != tt.want {
+			t.Errorf("config get %v = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+)
+
+func newContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage named contexts",
+		Long: `Manage named contexts, stored in ~/.ghr/contexts.yaml.
+
+A context bundles a config file, a Docker endpoint and a token reference, so
+one machine can manage runners for several orgs, repos or Docker hosts. Select
+one for all later commands with ` + "`ghr context use`" + `, or for a single command
+with --context.
+
+The context's config file replaces the discovered config files unless
+--config is given. Its Docker host and token take priority over GHR_* env
+vars; --set still overrides them.`,
+	}
+
+	cmd.AddCommand(
+		newContextAddCmd(),
+		newContextUseCmd(),
+		newContextListCmd(),
+		newContextCurrentCmd(),
+		newContextRemoveCmd(),
+	)
+	return cmd
+}
+
+func newContextAddCmd() *cobra.Command {
+	var (
+		c     config.Context
+		force bool
+		use   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add NAME",
+		Short: "Add a context",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			if _, ok := contexts.Contexts[name]; ok && !force {
+				return fmt.Errorf("context %q already exists; use --force to replace it", name)
+			}
+
+			if c.Config != "" {
+				abs, err := filepath.Abs(c.Config)
+				if err != nil {
+					return err
+				}
+				if _, err := os.Stat(abs); err != nil {
+					return fmt.Errorf("config file %s does not exist; create it with `ghr init --config %s`", abs, abs)
+				}
+				c.Config = abs
+			}
+			if c.DockerHost != "" {
+				check := config.Default()
+				check.Docker.Host = c.DockerHost
+				if err := config.ValidateKey(check, "docker.host"); err != nil {
+					return fmt.Errorf("invalid --docker-host: %w", err)
+				}
+			}
+
+			contexts.Contexts[name] = c
+			if use || contexts.Current == "" {
+				contexts.Current = name
+			}
+			if err := contexts.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("Added context %q\n", name)
+			if contexts.Current == name {
+				fmt.Printf("Switched to context %q\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&c.Config, "config", "", "config file for the context (default: the discovered config files)")
+	cmd.Flags().StringVar(&c.DockerHost, "docker-host", "", "Docker endpoint, e.g. tcp://build-host:2375 (sets docker.host)")
+	cmd.Flags().StringVar(&c.Token, "token-ref", "", "token, or env:VAR to read it from an environment variable (sets token)")
+	cmd.Flags().BoolVar(&force, "force", false, "replace an existing context")
+	cmd.Flags().BoolVar(&use, "use", false, "switch to the context after adding it")
+	return cmd
+}
+
+func newContextUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use NAME",
+		Short: "Switch the current context",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			if _, err := contexts.Get(args[0]); err != nil {
+				return err
+			}
+			contexts.Current = args[0]
+			if err := contexts.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("Switched to context %q\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newContextListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List contexts",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			list := contexts.List()
+			if len(list) == 0 {
+				fmt.Println("No contexts. Add one with `ghr context add NAME --config PATH`.")
+				return nil
+			}
+			current := contexts.Current
+			if cfgContext != "" {
+				current = cfgContext
+			}
+			output.PrintContextTable(os.Stdout, list, current)
+			return nil
+		},
+	}
+}
+
+func newContextCurrentCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "current",
+		Short: "Print the current context",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := activeContext()
+			if err != nil {
+				return err
+			}
+			if c == nil {
+				return fmt.Errorf("no current context; see `ghr context use`")
+			}
+			fmt.Println(c.Name)
+			return nil
+		},
+	}
+}
+
+func newContextRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove NAME",
+		Aliases: []string{"rm"},
+		Short:   "Remove a context",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contexts, err := config.LoadContexts()
+			if err != nil {
+				return err
+			}
+			if _, err := contexts.Get(args[0]); err != nil {
+				return err
+			}
+			delete(contexts.Contexts, args[0])
+			if contexts.Current == args[0] {
+				contexts.Current = ""
+			}
+			if err := contexts.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("Removed context %q\n", args[0])
+			return nil
+		},
+	}
+}
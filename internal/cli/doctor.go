@@ -0,0 +1,544 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+)
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check config, GitHub access and Docker setup",
+		Long: `Run a checklist of common setup problems and print a fix hint for each
+failure: config, token and scopes, access to the org or repo, the runner
+group, the Docker endpoint, the runner image, the Docker socket mount, the
+work directory, container name collisions and, on Podman, restart after
+reboot.
+
+Exits with a non-zero status if any check fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d := &doctor{}
+			d.run(cmd.Context())
+			output.PrintChecks(os.Stdout, d.results)
+
+			failed := 0
+			for _, r := range d.results {
+				if r.Status == output.CheckFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				fmt.Println()
+				return fmt.Errorf("%d check(s) failed", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// doctor runs the checks in order. Later checks are skipped when a check
+// they depend on failed.
+type doctor struct {
+	results []output.CheckResult
+
+	cfg   *config.Config
+	gh    *ghclient.Client
+	hosts []*doctorHost
+	// targetOK is set when the org, repo or enterprise was found.
+	targetOK bool
+}
+
+// doctorHost is a Docker host the Docker checks run against: the docker:
+// endpoint, or each fleet host.
+type doctorHost struct {
+	name     string // empty unless hosts are configured
+	conf     config.DockerConf
+	dc       *docker.Client
+	endpoint docker.Endpoint
+	podman   bool
+}
+
+// label returns the check name, qualified with the host name in fleet mode.
+func (h *doctorHost) label(check string) string {
+	if h.name == "" {
+		return check
+	}
+	return check + " (" + h.name + ")"
+}
+
+func (d *doctor) add(name, status, detail, hint string) {
+	d.results = append(d.results, output.CheckResult{Name: name, Status: status, Detail: detail, Hint: hint})
+}
+
+func (d *doctor) run(ctx context.Context) {
+	d.checkConfig()
+	d.checkToken(ctx)
+	d.checkTarget(ctx)
+	d.checkGroup(ctx)
+	for _, h := range d.dockerHosts() {
+		d.checkDocker(ctx, h)
+		if h.dc != nil {
+			defer h.dc.Close()
+		}
+		d.checkImage(ctx, h)
+		d.checkSocket(ctx, h)
+		d.checkWorkDir(h)
+		if h.podman {
+			d.checkPodmanRestart(h)
+		}
+		d.checkNames(ctx, h)
+	}
+}
+
+// dockerHosts returns the hosts to check. The default Docker settings are
+// used when the config did not load.
+func (d *doctor) dockerHosts() []*doctorHost {
+	if d.cfg == nil {
+		return []*doctorHost{{conf: config.Default().Docker}}
+	}
+	if len(d.cfg.Hosts) == 0 {
+		return []*doctorHost{{conf: d.cfg.Docker}}
+	}
+	var hosts []*doctorHost
+	for _, h := range d.cfg.Hosts {
+		hosts = append(hosts, &doctorHost{name: h.Name, conf: h.DockerConf(d.cfg.Docker)})
+	}
+	return hosts
+}
+
+func (d *doctor) checkConfig() {
+	const name = "Config"
+	loaded, err := loadConfig(cfgFile)
+	if err != nil {
+		d.add(name, output.CheckFail, err.Error(), "Run `ghr init` to create a config, or fix the file named above.")
+		return
+	}
+	d.cfg = loaded.Config
+	files := strings.Join(loaded.Files, ", ")
+	if err := loaded.Validate(); err != nil {
+		d.add(name, output.CheckFail, files+": "+err.Error(), "Fix the values above with `ghr config edit`; see `ghr config validate`.")
+		return
+	}
+	if len(loaded.Outdated) > 0 {
+		d.add(name, output.CheckWarn, files, "Run `ghr config migrate` to upgrade "+strings.Join(loaded.Outdated, ", ")+" to the current format.")
+		return
+	}
+	d.add(name, output.CheckOK, files, "")
+}
+
+func (d *doctor) checkToken(ctx context.Context) {
+	const name = "GitHub token"
+	if d.cfg == nil {
+		d.add(name, output.CheckSkip, "config did not load", "")
+		return
+	}
+	token, err := config.ResolveToken(d.cfg.Token)
+	if err != nil || token == "" {
+		d.add(name, output.CheckFail, fmt.Sprintf("could not resolve %q", d.cfg.Token),
+			"Export the variable named in `token`, add it to ~/.ghr/.env, or set `token` in the config.")
+		return
+	}
+	gh, err := ghclient.NewClient(ctx, token, d.cfg.GitHub.APIURL(), d.cfg.GitHub.UploadAPIURL())
+	if err != nil {
+		d.add(name, output.CheckFail, err.Error(), "")
+		return
+	}
+
+	need := ghclient.RequiredScopes(d.cfg.Scope)
+	scopes, reported, err := gh.TokenScopes(ctx)
+	switch {
+	case ghclient.StatusCode(err) == http.StatusUnauthorized:
+		d.add(name, output.CheckFail, "token was rejected by GitHub",
+			"The token is invalid, expired or revoked; create a new one at "+d.cfg.GitHub.WebURL()+"/settings/tokens.")
+		return
+	case err != nil:
+		d.add(name, output.CheckFail, err.Error(), d.networkHint())
+		return
+	}
+	d.gh = gh
+
+	if !reported {
+		d.add(name, output.CheckWarn, "token does not report scopes (fine-grained or app token)",
+			"Make sure it has read and write access to self-hosted runners ("+d.scopeTarget()+").")
+		return
+	}
+	if missing := ghclient.MissingScopes(scopes, need); len(missing) > 0 {
+		d.add(name, output.CheckFail, "missing scopes: "+strings.Join(missing, ", "),
+			"Create a classic token with these scopes: "+d.cfg.GitHub.WebURL()+"/settings/tokens/new?scopes="+strings.Join(need, ","))
+		return
+	}
+	d.add(name, output.CheckOK, "scopes: "+strings.Join(scopes, ", "), "")
+}
+
+func (d *doctor) scopeTarget() string {
+	return d.cfg.Scope + " " + d.cfg.Target()
+}
+
+// networkHint suggests checking access to the configured API host.
+func (d *doctor) networkHint() string {
+	host := "api.github.com"
+	if u, err := url.Parse(d.cfg.GitHub.APIURL()); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return "Check network access to " + host + "."
+}
+
+func (d *doctor) checkTarget(ctx context.Context) {
+	if d.cfg == nil {
+		d.add("GitHub access", output.CheckSkip, "config did not load", "")
+		return
+	}
+	// Each repo in repos: is checked on its own.
+	for _, p := range d.cfg.Pools() {
+		d.checkTargetOf(ctx, p)
+	}
+}
+
+func (d *doctor) checkTargetOf(ctx context.Context, p *config.Config) {
+	name := "GitHub " + p.Scope
+	if len(d.cfg.Repos) > 0 {
+		name += " " + p.Target()
+	}
+	if d.gh == nil {
+		d.add(name, output.CheckSkip, "no usable token", "")
+		return
+	}
+
+	// There is no enterprise lookup for tokens; listing runners below checks
+	// the slug and access together.
+	var err error
+	switch p.Scope {
+	case "org":
+		err = d.gh.CheckOrg(ctx, p.Org)
+	case "repo":
+		err = d.gh.CheckRepo(ctx, p.Repo.Owner, p.Repo.Name)
+	}
+	if err != nil {
+		hint := d.networkHint()
+		if ghclient.StatusCode(err) == http.StatusNotFound {
+			hint = "Check the name in the config, and that the token's user can see the " + p.Scope + "."
+		}
+		d.add(name, output.CheckFail, err.Error(), hint)
+		return
+	}
+
+	// Listing runners needs admin access, which the lookup above does not.
+	var runners []ghclient.RunnerStatus
+	switch p.Scope {
+	case "org":
+		runners, err = d.gh.ListOrgRunners(ctx, p.Org)
+	case "enterprise":
+		runners, err = d.gh.ListEnterpriseRunners(ctx, p.Enterprise)
+	default:
+		runners, err = d.gh.ListRepoRunners(ctx, p.Repo.Owner, p.Repo.Name)
+	}
+	if err != nil {
+		d.add(name, output.CheckFail, err.Error(),
+			"The token's user needs admin access to the "+p.Scope+" to manage its self-hosted runners.")
+		return
+	}
+	d.targetOK = true
+	d.add(name, output.CheckOK, fmt.Sprintf("%s %s (%d runners registered)", p.Scope, p.Target(), len(runners)), "")
+}
+
+// checkGroup checks that runners.group exists in the org, so runners do not
+// fail to register. Only org runners are placed in groups by ghr.
+func (d *doctor) checkGroup(ctx context.Context) {
+	if d.cfg == nil || d.cfg.Scope != "org" {
+		return
+	}
+	name := "Runner group"
+	if d.cfg.Runners.Group == "" {
+		d.add(name, output.CheckOK, "not set; runners join the org's default group", "")
+		return
+	}
+	if !d.targetOK {
+		d.add(name, output.CheckSkip, "org not checked", "")
+		return
+	}
+	g, err := d.gh.FindRunnerGroup(ctx, d.cfg.Org, d.cfg.Runners.Group)
+	var notFound *ghclient.GroupNotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		d.add(name, output.CheckFail, err.Error(),
+			"Create it with `ghr groups create "+d.cfg.Runners.Group+"`, or set runners.group to an existing group.")
+		return
+	case err != nil:
+		d.add(name, output.CheckFail, err.Error(), "Listing runner groups needs admin access to the org.")
+		return
+	}
+	d.add(name, output.CheckOK, fmt.Sprintf("%s (visibility %s)", g.Name, g.Visibility), "")
+}
+
+func (d *doctor) checkDocker(ctx context.Context, h *doctorHost) {
+	name := h.label("Docker")
+	ep, err := docker.ResolveEndpoint(h.conf)
+	if err != nil {
+		d.add(name, output.CheckFail, err.Error(), "Set docker.context to a context listed by `docker context ls`.")
+		return
+	}
+	h.endpoint = ep
+	where := ep.String()
+	hint := "Make sure Docker is running and reachable at " + where + "; set docker.host or DOCKER_HOST, switch `docker context`, or change docker.socket."
+	if h.name != "" {
+		if ep.Source == docker.SourceHost {
+			where = ep.Host
+		}
+		hint = "Make sure Docker is running and reachable at " + where + ", or fix the host's entry under hosts."
+	}
+	if ep.SSH() {
+		hint = "Make sure `docker -H " + ep.Host + " version` works without a password prompt; ghr runs ssh the same way."
+	}
+	if ep.Source == docker.SourcePodman {
+		hint = "Make sure the Podman API service is running: `systemctl --user enable --now podman.socket`, or `sudo systemctl enable --now podman.socket` for rootful Podman."
+	}
+	if err := ep.CheckTLSFiles(); err != nil {
+		tlsHint := "Fix the cert, key and ca paths under docker.tls, or under the host's tls in hosts."
+		if ep.Source == docker.SourceContext {
+			tlsHint = "Recreate the Docker context with `docker context create`, which stores its TLS files."
+		}
+		d.add(name, output.CheckFail, err.Error(), tlsHint)
+		return
+	}
+
+	dc, err := docker.NewClient(h.conf)
+	if err != nil {
+		d.add(name, output.CheckFail, err.Error(), hint)
+		return
+	}
+	v, err := dc.ServerVersion(ctx)
+	if err != nil {
+		dc.Close()
+		d.add(name, output.CheckFail, where+": "+err.Error(), hint)
+		return
+	}
+	h.dc = dc
+
+	engine := "Docker"
+	if docker.IsPodman(v) {
+		engine = "Podman"
+		h.podman = true
+	}
+	detail := fmt.Sprintf("%s, %s %s, API %s", where, engine, v.Version, v.APIVersion)
+	if !docker.APIVersionSupported(v.APIVersion) {
+		d.add(name, output.CheckFail, detail, "ghr needs Docker API "+docker.MinAPIVersion+" or newer; upgrade "+engine+".")
+		return
+	}
+	d.add(name, output.CheckOK, detail, "")
+}
+
+func (d *doctor) checkImage(ctx context.Context, h *doctorHost) {
+	name := h.label("Runner image")
+	if d.cfg == nil || h.dc == nil {
+		d.add(name, output.CheckSkip, "Docker is not available", "")
+		return
+	}
+	ref := d.cfg.Runners.Image
+	ok, err := h.dc.ImageExists(ctx, ref)
+	if err != nil {
+		d.add(name, output.CheckFail, err.Error(), "")
+		return
+	}
+	if ok {
+		d.add(name, output.CheckOK, ref+" (present locally)", "")
+		return
+	}
+	if err := h.dc.RegistryImageExists(ctx, ref); err != nil {
+		d.add(name, output.CheckFail, err.Error(),
+			"Check runners.image; for a private registry run `docker login` on the Docker host.")
+		return
+	}
+	d.add(name, output.CheckWarn, ref+" is in the registry but not pulled",
+		"Run `docker pull "+ref+"` before `ghr up`.")
+}
+
+// local reports whether the Docker endpoint is a local socket, so paths in
+// the config can be checked on this machine.
+func (h *doctorHost) local() bool {
+	return strings.HasPrefix(h.endpoint.Host, "unix://")
+}
+
+func (d *doctor) checkSocket(ctx context.Context, h *doctorHost) {
+	name := h.label("Docker socket mount")
+	switch {
+	case d.cfg == nil:
+		d.add(name, output.CheckSkip, "config did not load", "")
+		return
+	case !h.conf.MountDockerSocket:
+		d.add(name, output.CheckSkip, "mount_docker_socket is off", "")
+		return
+	case !h.local():
+		d.add(name, output.CheckSkip, "Docker host is remote; not checked", "")
+		return
+	}
+	socket := h.conf.Socket
+	if h.dc != nil {
+		if s, err := h.dc.MountSocket(ctx, h.conf); err == nil {
+			socket = s
+		}
+	}
+	info, err := os.Stat(socket)
+	switch {
+	case err != nil:
+		d.add(name, output.CheckFail, socket+" does not exist",
+			"Set docker.socket to the Docker socket path, or set docker.mount_docker_socket to false.")
+	case info.Mode()&os.ModeSocket == 0:
+		d.add(name, output.CheckFail, socket+" is not a socket",
+			"Set docker.socket to the Docker socket path.")
+	default:
+		d.add(name, output.CheckOK, socket, "")
+	}
+}
+
+// checkPodmanRestart warns when Podman will not bring runners back after a
+// reboot. Podman has no daemon to restart containers at boot; that is left to
+// podman-restart.service.
+func (d *doctor) checkPodmanRestart(h *doctorHost) {
+	name := h.label("Podman restart")
+	switch {
+	case d.cfg == nil:
+		d.add(name, output.CheckSkip, "config did not load", "")
+		return
+	case h.conf.RestartPolicy != "always" && h.conf.RestartPolicy != "unless-stopped":
+		d.add(name, output.CheckSkip, "restart_policy is "+h.conf.RestartPolicy, "")
+		return
+	case !h.local():
+		d.add(name, output.CheckSkip, "Podman host is remote; not checked", "")
+		return
+	}
+	if podmanRestartEnabled() {
+		d.add(name, output.CheckOK, "podman-restart.service is enabled", "")
+		return
+	}
+	d.add(name, output.CheckWarn, "podman-restart.service is not enabled; runners will not start again after a reboot",
+		"Run `systemctl --user enable podman-restart.service` and `loginctl enable-linger $USER`, or `sudo systemctl enable podman-restart.service` for rootful Podman.")
+}
+
+// podmanRestartEnabled reports whether podman-restart.service is enabled on
+// this machine for the user or the system.
+func podmanRestartEnabled() bool {
+	// Where systemd records the unit as enabled for the user and the system.
+	home, _ := os.UserHomeDir()
+	units := []string{
+		filepath.Join(home, ".config/systemd/user/default.target.wants/podman-restart.service"),
+		"/etc/systemd/system/default.target.wants/podman-restart.service",
+	}
+	for _, unit := range units {
+		if _, err := os.Stat(unit); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// warnPodmanRestart warns before runners are created on a local Podman host
+// whose restart policy will not be acted on after a reboot, because
+// podman-restart.service is not enabled.
+func warnPodmanRestart(ctx context.Context) {
+	if p := cfg.Docker.RestartPolicy; p != "always" && p != "unless-stopped" {
+		return
+	}
+	for _, h := range mgr.Hosts {
+		dc, ok := h.Runtime.(*docker.Client)
+		if !ok {
+			continue
+		}
+		if podman, err := dc.Podman(ctx); err != nil || !podman {
+			continue
+		}
+		conf := cfg.Docker
+		for _, hc := range cfg.Hosts {
+			if hc.Name == h.Name {
+				conf = hc.DockerConf(cfg.Docker)
+			}
+		}
+		ep, err := docker.ResolveEndpoint(conf)
+		if err != nil || !strings.HasPrefix(ep.Host, "unix://") || podmanRestartEnabled() {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Warning: podman-restart.service is not enabled, so Podman ignores restart_policy %q after a reboot; run `ghr doctor` for how to enable it.\n", cfg.Docker.RestartPolicy)
+		return
+	}
+}
+
+func (d *doctor) checkWorkDir(h *doctorHost) {
+	name := h.label("Work directory")
+	switch {
+	case d.cfg == nil:
+		d.add(name, output.CheckSkip, "config did not load", "")
+		return
+	case h.conf.WorkDirBase == "":
+		d.add(name, output.CheckSkip, "work_dir_base not set; named volumes are used", "")
+		return
+	case !h.local():
+		d.add(name, output.CheckSkip, "Docker host is remote; not checked", "")
+		return
+	}
+	dir := h.conf.WorkDirBase
+	f, err := os.CreateTemp(dir, ".ghr-doctor-*")
+	if err != nil {
+		d.add(name, output.CheckFail, dir+" is not writable: "+err.Error(),
+			"Create the directory and make it writable, e.g. `sudo mkdir -p "+dir+" && sudo chown $USER "+dir+"`.")
+		return
+	}
+	f.Close()
+	os.Remove(f.Name())
+	d.add(name, output.CheckOK, dir+" is writable", "")
+}
+
+func (d *doctor) checkNames(ctx context.Context, h *doctorHost) {
+	name := h.label("Container names")
+	if d.cfg == nil || h.dc == nil {
+		d.add(name, output.CheckSkip, "Docker is not available", "")
+		return
+	}
+	names, err := h.dc.UnmanagedContainerNames(ctx)
+	if err != nil {
+		d.add(name, output.CheckFail, err.Error(), "")
+		return
+	}
+	// Each repo in repos: names its runners with its own prefix.
+	var collisions, keys, patterns []string
+	for i, p := range d.cfg.Pools() {
+		prefix, key := p.Runners.NamePrefix, "runners.name_prefix"
+		if len(d.cfg.Repos) > 0 {
+			key = fmt.Sprintf("repos[%d].name_prefix", i)
+		}
+		patterns = append(patterns, prefix+"-runner-N")
+		re := config.NamePattern(prefix)
+		var clash []string
+		for _, n := range names {
+			if re.MatchString(n) {
+				clash = append(clash, n)
+			}
+		}
+		if len(clash) == 0 {
+			continue
+		}
+		found := strings.Join(clash, ", ")
+		if len(d.cfg.Repos) > 0 {
+			found += " (" + p.Repo.FullName() + ")"
+		}
+		collisions = append(collisions, found)
+		keys = append(keys, key)
+	}
+	if len(collisions) > 0 {
+		d.add(name, output.CheckFail, "not managed by ghr: "+strings.Join(collisions, "; "),
+			"Remove or rename these containers, or change "+strings.Join(keys, " and ")+".")
+		return
+	}
+	d.add(name, output.CheckOK, "no conflicts with "+strings.Join(patterns, ", "), "")
+}
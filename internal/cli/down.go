@@ -5,16 +5,23 @@ import (
 	"strconv"
 
 	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
 )
 
 func newDownCmd() *cobra.Command {
-	var all bool
+	var (
+		all   bool
+		repos []string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "down [COUNT]",
 		Short: "Stop and remove runners",
-		Long:  "Stop and remove COUNT runners (highest-numbered first). Use --all to remove all.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Stop and remove COUNT runners (highest-numbered first). Use --all to remove all.
+
+With a repos: list, COUNT runners are removed from each repo; --repo limits
+this to the given repos.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if !all && len(args) == 0 {
 				return fmt.Errorf("specify COUNT or use --all")
@@ -27,10 +34,16 @@ func newDownCmd() *cobra.Command {
 					return fmt.Errorf("invalid count: %s", args[0])
 				}
 			}
-			return mgr.Down(cmd.Context(), count, all)
+			if all && len(repos) == 0 {
+				return mgr.Down(cmd.Context(), 0, true)
+			}
+			return forEachPool(repos, func(p *runner.Manager) error {
+				return p.Down(cmd.Context(), count, all)
+			})
 		},
 	}
 
 	cmd.Flags().BoolVar(&all, "all", false, "remove all managed runners")
+	cmd.Flags().StringSliceVar(&repos, "repo", nil, "only these repos from repos:, as OWNER/NAME (repeatable)")
 	return cmd
 }
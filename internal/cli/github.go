@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+)
+
+// newGitHubClient resolves the configured token and returns an API client.
+func newGitHubClient(ctx context.Context) (*ghclient.Client, error) {
+	token, err := config.ResolveToken(cfg.Token)
+	if err != nil {
+		return nil, fmt.Errorf("resolving token for GitHub API: %w", err)
+	}
+	return ghclient.NewClient(ctx, token, cfg.GitHub.APIURL(), cfg.GitHub.UploadAPIURL())
+}
+
+// listGitHubRunners lists the runners registered for the configured scope,
+// including every repo in repos:.
+func listGitHubRunners(ctx context.Context, ghc *ghclient.Client) ([]ghclient.RunnerStatus, error) {
+	switch cfg.Scope {
+	case "org":
+		return ghc.ListOrgRunners(ctx, cfg.Org)
+	case "enterprise":
+		return ghc.ListEnterpriseRunners(ctx, cfg.Enterprise)
+	}
+	var all []ghclient.RunnerStatus
+	for _, p := range cfg.Pools() {
+		runners, err := ghc.ListRepoRunners(ctx, p.Repo.Owner, p.Repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, runners...)
+	}
+	return all, nil
+}
+
+// listGitHubJobs lists workflow jobs created since the given time for the
+// configured scope. GitHub has no enterprise-wide job listing.
+func listGitHubJobs(ctx context.Context, ghc *ghclient.Client, since time.Time) ([]ghclient.WorkflowJob, error) {
+	switch cfg.Scope {
+	case "org":
+		return ghc.ListOrgJobs(ctx, cfg.Org, since)
+	case "enterprise":
+		return nil, fmt.Errorf("workflow jobs cannot be listed for an enterprise; use an org or repo config")
+	}
+	var all []ghclient.WorkflowJob
+	for _, p := range cfg.Pools() {
+		jobs, err := ghc.ListRepoJobs(ctx, p.Repo.Owner, p.Repo.Name, since)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, jobs...)
+	}
+	return all, nil
+}
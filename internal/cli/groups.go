@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+)
+
+func newGroupsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "groups",
+		Short: "Manage org runner groups",
+		Long: `Manage the runner groups of the configured org through the GitHub API:
+which repositories and workflows may use each group's runners.
+
+Runners join the group named by runners.group when they register. Creating
+the group here first, and giving it access to the right repositories, saves a
+trip to the org's Actions settings. Requires scope: org and a token with
+admin:org.`,
+	}
+
+	cmd.AddCommand(
+		newGroupsListCmd(),
+		newGroupsCreateCmd(),
+		newGroupsDeleteCmd(),
+		newGroupsSetReposCmd(),
+		newGroupsSetWorkflowsCmd(),
+	)
+	return cmd
+}
+
+// newGroupsClient returns an API client for managing the configured org's
+// runner groups.
+func newGroupsClient(ctx context.Context) (*ghclient.Client, error) {
+	if cfg.Scope != "org" {
+		return nil, fmt.Errorf("runner groups are managed per org; `ghr groups` needs scope: org (have %s)", cfg.Scope)
+	}
+	return newGitHubClient(ctx)
+}
+
+func newGroupsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List runner groups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ghc, err := newGroupsClient(ctx)
+			if err != nil {
+				return err
+			}
+			groups, err := ghc.ListRunnerGroups(ctx, cfg.Org)
+			if err != nil {
+				return err
+			}
+			repos := make(map[int64][]string)
+			for _, g := range groups {
+				if g.Visibility != "selected" {
+					continue
+				}
+				if repos[g.ID], err = ghc.RunnerGroupRepos(ctx, cfg.Org, g.ID); err != nil {
+					return err
+				}
+			}
+			output.PrintGroupTable(os.Stdout, groups, repos, cfg.Runners.Group)
+			return nil
+		},
+	}
+}
+
+func newGroupsCreateCmd() *cobra.Command {
+	var opts ghclient.RunnerGroupOptions
+
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a runner group",
+		Long: `Create a runner group in the configured org.
+
+Repositories are given as NAME for a repository in the org, or OWNER/NAME.
+Giving --repos implies --visibility selected. Workflows are given as
+OWNER/REPO/PATH@REF, e.g. myorg/app/.github/workflows/deploy.yml@refs/heads/main;
+without --workflows, any workflow may use the group's runners. Use set-repos
+and set-workflows to change them later.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("visibility") && len(opts.Repos) > 0 {
+				opts.Visibility = "selected"
+			}
+			switch opts.Visibility {
+			case "all", "selected", "private":
+			default:
+				return fmt.Errorf("invalid --visibility %q: must be 'all', 'selected' or 'private'", opts.Visibility)
+			}
+			if len(opts.Repos) > 0 && opts.Visibility != "selected" {
+				return fmt.Errorf("--repos needs --visibility selected")
+			}
+
+			ctx := cmd.Context()
+			ghc, err := newGroupsClient(ctx)
+			if err != nil {
+				return err
+			}
+			g, err := ghc.CreateRunnerGroup(ctx, cfg.Org, args[0], opts)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Created runner group %q in %s (visibility %s)\n", g.Name, cfg.Org, g.Visibility)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Visibility, "visibility", "all", "which repositories may use the group: all, selected or private")
+	cmd.Flags().StringSliceVar(&opts.Repos, "repos", nil, "repositories that may use the group (implies --visibility selected)")
+	cmd.Flags().StringSliceVar(&opts.Workflows, "workflows", nil, "restrict the group to these workflows (OWNER/REPO/PATH@REF)")
+	cmd.Flags().BoolVar(&opts.AllowsPublicRepos, "allow-public", false, "let public repositories use the group")
+	return cmd
+}
+
+func newGroupsDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete a runner group",
+		Long: `Delete a runner group from the configured org. Its runners move to the
+org's default group. The default group cannot be deleted.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ghc, err := newGroupsClient(ctx)
+			if err != nil {
+				return err
+			}
+			if err := ghc.DeleteRunnerGroup(ctx, cfg.Org, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted runner group %q from %s\n", args[0], cfg.Org)
+			if args[0] == cfg.Runners.Group {
+				fmt.Fprintf(os.Stderr, "Note: runners.group is still %q; new runners will fail to register.\n", args[0])
+			}
+			return nil
+		},
+	}
+}
+
+func newGroupsSetReposCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-repos NAME [REPO...]",
+		Short: "Replace the repositories that may use a runner group",
+		Long: `Replace the repositories that may use a runner group. Repositories are
+given as NAME for a repository in the org, or OWNER/NAME. A group visible to
+all repositories is switched to selected ones; with no repositories, none may
+use the group.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ghc, err := newGroupsClient(ctx)
+			if err != nil {
+				return err
+			}
+			if err := ghc.SetRunnerGroupRepos(ctx, cfg.Org, args[0], args[1:]); err != nil {
+				return err
+			}
+			fmt.Printf("Runner group %q is now available to %d repositories\n", args[0], len(args)-1)
+			return nil
+		},
+	}
+}
+
+func newGroupsSetWorkflowsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-workflows NAME [WORKFLOW...]",
+		Short: "Replace the workflows that may use a runner group",
+		Long: `Replace the workflows that may use a runner group. Workflows are given as
+OWNER/REPO/PATH@REF, e.g. myorg/app/.github/workflows/deploy.yml@refs/heads/main.
+With no workflows, the restriction is lifted and any workflow may use the
+group.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			ghc, err := newGroupsClient(ctx)
+			if err != nil {
+				return err
+			}
+			if err := ghc.SetRunnerGroupWorkflows(ctx, cfg.Org, args[0], args[1:]); err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				fmt.Printf("Runner group %q is now available to any workflow\n", args[0])
+				return nil
+			}
+			fmt.Printf("Runner group %q is now restricted to %d workflows\n", args[0], len(args)-1)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
+)
+
+func newHistoryCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "history [NAME_OR_NUMBER...]",
+		Short: "Show workflow jobs executed by runners (GitHub API)",
+		Long: `Show the workflow jobs that ran on managed runners, as recorded by the
+GitHub API. Without arguments, jobs from every runner whose name matches
+the configured name_prefix are shown, including runners whose containers
+have since been removed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseWindow(since)
+			if err != nil {
+				return err
+			}
+
+			match, err := runnerNameMatcher(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
+
+			ghc, err := newGitHubClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			all, err := listGitHubJobs(cmd.Context(), ghc, time.Now().Add(-window))
+			if err != nil {
+				return fmt.Errorf("fetching workflow jobs: %w", err)
+			}
+
+			var jobs []ghclient.WorkflowJob
+			for _, j := range all {
+				if match(j.RunnerName) {
+					jobs = append(jobs, j)
+				}
+			}
+			if len(jobs) == 0 {
+				fmt.Printf("No jobs found in the last %s.\n", since)
+				return nil
+			}
+			sort.Slice(jobs, func(i, j int) bool {
+				return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+			})
+
+			output.PrintHistoryTable(os.Stdout, jobs)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "24h", "how far back to look (e.g. 90m, 24h, 7d)")
+	return cmd
+}
+
+// runnerNameMatcher returns a predicate over GitHub runner names. Refs are
+// resolved against existing containers first; numbers and names that no
+// longer have a container are still accepted so removed runners can be
+// looked up. A number then matches every runner name with that number that
+// fits the name prefix of the config or of a repo in repos:, as the
+// prefix may be a template of the host or pool.
+func runnerNameMatcher(ctx context.Context, refs []string) (func(string) bool, error) {
+	var patterns []*regexp.Regexp
+	for _, p := range cfg.Pools() {
+		patterns = append(patterns, config.NamePattern(p.Runners.NamePrefix))
+	}
+	managed := func(name string) bool {
+		for _, re := range patterns {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(refs) == 0 {
+		return managed, nil
+	}
+
+	names := make(map[string]bool)
+	nums := make(map[string]bool)
+	for _, ref := range refs {
+		matched, err := mgr.Select(ctx, runner.Selector{Refs: []string{ref}})
+		if err == nil {
+			for _, c := range matched {
+				names[c.Name] = true
+			}
+			continue
+		}
+		if _, convErr := strconv.Atoi(ref); convErr == nil {
+			nums[ref] = true
+		} else {
+			names[ref] = true
+		}
+	}
+	return func(name string) bool {
+		if names[name] {
+			return true
+		}
+		i := strings.LastIndex(name, "-runner-")
+		return i >= 0 && nums[name[i+len("-runner-"):]] && managed(name)
+	}, nil
+}
+
+// parseWindow parses a look-back window. In addition to Go durations it
+// accepts a whole number of days, e.g. "7d".
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -2,32 +2,69 @@ package cli
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
 )
 
+// initFlags holds the values given on the command line. Empty values are
+// prompted for, or take their default with --yes.
+type initFlags struct {
+	scope      string
+	org        string
+	repo       string
+	enterprise string
+	image      string
+	labels     []string
+	group      string
+	prefix     string
+	tokenRef   string
+}
+
 func newInitCmd() *cobra.Command {
-	var importEnv bool
+	var (
+		importEnv bool
+		yes       bool
+		force     bool
+		check     bool
+		f         initFlags
+	)
 
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Create config file in ~/.ghr/",
+		Long: `Create a config file, by default ~/.ghr/config.yaml.
+
+Values given as flags are used as-is; anything else is prompted for, with
+the default in brackets. Invalid answers are asked again. With --yes, no
+prompts are shown and defaults are used for anything not given, so init can
+run unattended in provisioning scripts.
+
+For scope org, the runner group is looked up with the GitHub API whenever
+the token resolves; a group that does not exist is asked again, or fails
+init with --yes. With --check, the org, repo or enterprise and the runner group are looked
+up with the GitHub API before the config is written.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			outPath := config.DefaultConfigPath()
 			if cfgFile != "" {
 				outPath = cfgFile
 			}
+			p := &prompter{reader: bufio.NewReader(os.Stdin), yes: yes}
 
-			if _, err := os.Stat(outPath); err == nil {
-				fmt.Printf("Config file %s already exists. Overwrite? [y/N] ", outPath)
-				reader := bufio.NewReader(os.Stdin)
-				answer, _ := reader.ReadString('\n')
-				if strings.TrimSpace(strings.ToLower(answer)) != "y" {
+			if _, err := os.Stat(outPath); err == nil && !force {
+				if yes {
+					return fmt.Errorf("config file %s already exists; use --force to overwrite it", outPath)
+				}
+				if !p.confirm(fmt.Sprintf("Config file %s already exists. Overwrite?", outPath), false) {
 					fmt.Println("Aborted.")
 					return nil
 				}
@@ -37,85 +74,36 @@ func newInitCmd() *cobra.Command {
 
 			// Try to import from .env if requested or if .env exists
 			// Check both ~/.ghr/.env and ./.env
-			envPath := ""
-			if importEnv {
-				envPath = findEnvFile()
-				if envPath != "" {
-					if err := importFromEnvFile(newCfg, envPath); err != nil {
-						fmt.Printf("Warning: could not import %s: %v\n", envPath, err)
-					}
+			envPath := findEnvFile()
+			switch {
+			case importEnv && envPath == "":
+				fmt.Println("Warning: no .env file found")
+			case envPath != "" && (importEnv || p.confirm(fmt.Sprintf("Found %s. Import settings?", envPath), true)):
+				if err := importFromEnvFile(newCfg, envPath); err != nil {
+					fmt.Printf("Warning: could not import %s: %v\n", envPath, err)
 				} else {
-					fmt.Println("Warning: no .env file found")
-				}
-			} else {
-				envPath = findEnvFile()
-				if envPath != "" {
-					fmt.Printf("Found %s. Import settings? [Y/n] ", envPath)
-					reader := bufio.NewReader(os.Stdin)
-					answer, _ := reader.ReadString('\n')
-					answer = strings.TrimSpace(strings.ToLower(answer))
-					if answer == "" || answer == "y" {
-						if err := importFromEnvFile(newCfg, envPath); err != nil {
-							fmt.Printf("Warning: could not import %s: %v\n", envPath, err)
-						} else {
-							fmt.Printf("Imported settings from %s\n", envPath)
-						}
-					}
-				}
-			}
-
-			// Interactive prompts
-			reader := bufio.NewReader(os.Stdin)
-
-			fmt.Printf("Scope [%s]: ", newCfg.Scope)
-			if s := readLine(reader); s != "" {
-				newCfg.Scope = s
-			}
-
-			if newCfg.Scope == "org" {
-				fmt.Printf("Organization [%s]: ", newCfg.Org)
-				if s := readLine(reader); s != "" {
-					newCfg.Org = s
-				}
-			} else {
-				fmt.Printf("Repo owner [%s]: ", newCfg.Repo.Owner)
-				if s := readLine(reader); s != "" {
-					newCfg.Repo.Owner = s
-				}
-				fmt.Printf("Repo name [%s]: ", newCfg.Repo.Name)
-				if s := readLine(reader); s != "" {
-					newCfg.Repo.Name = s
+					fmt.Printf("Imported settings from %s\n", envPath)
 				}
 			}
 
-			fmt.Printf("Token reference [%s]: ", newCfg.Token)
-			if s := readLine(reader); s != "" {
-				newCfg.Token = s
+			if err := f.apply(newCfg); err != nil {
+				return err
 			}
-
-			fmt.Printf("Runner image [%s]: ", newCfg.Runners.Image)
-			if s := readLine(reader); s != "" {
-				newCfg.Runners.Image = s
+			if err := promptConfig(cmd.Context(), p, newCfg, f); err != nil {
+				return err
 			}
 
-			fmt.Printf("Labels [%s]: ", strings.Join(newCfg.Runners.Labels, ","))
-			if s := readLine(reader); s != "" {
-				newCfg.Runners.Labels = strings.Split(s, ",")
-				for i := range newCfg.Runners.Labels {
-					newCfg.Runners.Labels[i] = strings.TrimSpace(newCfg.Runners.Labels[i])
+			if check {
+				if err := checkInitTarget(cmd.Context(), newCfg); err != nil {
+					fmt.Printf("Check failed: %v\n", err)
+					if yes || !p.confirm("Write config anyway?", false) {
+						return fmt.Errorf("config not written: %w", err)
+					}
+				} else {
+					fmt.Println("GitHub check passed.")
 				}
 			}
 
-			fmt.Printf("Runner group [%s]: ", newCfg.Runners.Group)
-			if s := readLine(reader); s != "" {
-				newCfg.Runners.Group = s
-			}
-
-			fmt.Printf("Name prefix [%s]: ", newCfg.Runners.NamePrefix)
-			if s := readLine(reader); s != "" {
-				newCfg.Runners.NamePrefix = s
-			}
-
 			if err := config.Save(newCfg, outPath); err != nil {
 				return err
 			}
@@ -125,9 +113,294 @@ func newInitCmd() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&importEnv, "import-env", false, "import settings from .env file")
+	cmd.Flags().StringVar(&f.scope, "scope", "", `runner scope, "org", "repo" or "enterprise"`)
+	cmd.Flags().StringVar(&f.org, "org", "", "organization name (implies --scope org)")
+	cmd.Flags().StringVar(&f.repo, "repo", "", "repository as OWNER/NAME (implies --scope repo)")
+	cmd.Flags().StringVar(&f.enterprise, "enterprise", "", "enterprise slug (implies --scope enterprise)")
+	cmd.Flags().StringVar(&f.image, "image", "", "runner image")
+	cmd.Flags().StringSliceVar(&f.labels, "labels", nil, "runner labels, comma-separated")
+	cmd.Flags().StringVar(&f.group, "group", "", "runner group")
+	cmd.Flags().StringVar(&f.prefix, "prefix", "", "container name prefix")
+	cmd.Flags().StringVar(&f.tokenRef, "token-ref", "", "token, or env:VAR to read it from an environment variable")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "do not prompt; use defaults for values not given as flags")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite an existing config file")
+	cmd.Flags().BoolVar(&check, "check", false, "check that the org, repo or enterprise and runner group exist on GitHub before writing")
 	return cmd
 }
 
+// apply copies the given flags into cfg and validates them.
+func (f initFlags) apply(cfg *config.Config) error {
+	targets := 0
+	for _, v := range []string{f.org, f.repo, f.enterprise} {
+		if v != "" {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return fmt.Errorf("--org, --repo and --enterprise are mutually exclusive")
+	}
+	switch {
+	case f.scope != "":
+		cfg.Scope = f.scope
+	case f.org != "":
+		cfg.Scope = "org"
+	case f.repo != "":
+		cfg.Scope = "repo"
+	case f.enterprise != "":
+		cfg.Scope = "enterprise"
+	}
+	if f.org != "" {
+		cfg.Org = f.org
+	}
+	if f.enterprise != "" {
+		cfg.Enterprise = f.enterprise
+	}
+	if f.repo != "" {
+		owner, name, ok := strings.Cut(f.repo, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("invalid --repo %q; expected OWNER/NAME", f.repo)
+		}
+		cfg.Repo = config.RepoConfig{Owner: owner, Name: name}
+	}
+	if f.image != "" {
+		cfg.Runners.Image = f.image
+	}
+	if f.labels != nil {
+		cfg.Runners.Labels = trimAll(f.labels)
+	}
+	if f.group != "" {
+		cfg.Runners.Group = f.group
+	}
+	if f.prefix != "" {
+		cfg.Runners.NamePrefix = f.prefix
+	}
+	if f.tokenRef != "" {
+		cfg.Token = f.tokenRef
+	}
+
+	given := []struct {
+		flag string
+		key  string
+		set  bool
+	}{
+		{"--scope", "scope", f.scope != ""},
+		{"--org", "org", f.org != ""},
+		{"--enterprise", "enterprise", f.enterprise != ""},
+		{"--token-ref", "token", f.tokenRef != ""},
+		{"--image", "runners.image", f.image != ""},
+		{"--labels", "runners.labels", f.labels != nil},
+		{"--prefix", "runners.name_prefix", f.prefix != ""},
+	}
+	for _, g := range given {
+		if !g.set {
+			continue
+		}
+		if err := config.ValidateKey(cfg, g.key); err != nil {
+			return fmt.Errorf("invalid %s: %s: %w", g.flag, g.key, err)
+		}
+	}
+	return nil
+}
+
+// promptConfig asks for every value not given as a flag.
+func promptConfig(ctx context.Context, p *prompter, cfg *config.Config, f initFlags) error {
+	validKey := func(key string) func(string) error {
+		return func(string) error { return config.ValidateKey(cfg, key) }
+	}
+
+	if f.scope == "" && f.org == "" && f.repo == "" && f.enterprise == "" {
+		if err := p.ask("Scope", &cfg.Scope, validKey("scope")); err != nil {
+			return err
+		}
+	}
+	switch {
+	case cfg.Scope == "org":
+		if f.org == "" {
+			if err := p.ask("Organization", &cfg.Org, validKey("org")); err != nil {
+				return err
+			}
+		}
+	case cfg.Scope == "enterprise":
+		if f.enterprise == "" {
+			if err := p.ask("Enterprise", &cfg.Enterprise, validKey("enterprise")); err != nil {
+				return err
+			}
+		}
+	case f.repo == "":
+		if err := p.ask("Repo owner", &cfg.Repo.Owner, validKey("repo.owner")); err != nil {
+			return err
+		}
+		if err := p.ask("Repo name", &cfg.Repo.Name, validKey("repo.name")); err != nil {
+			return err
+		}
+	}
+	if f.tokenRef == "" {
+		if err := p.ask("Token reference", &cfg.Token, validKey("token")); err != nil {
+			return err
+		}
+	}
+	if f.image == "" {
+		if err := p.ask("Runner image", &cfg.Runners.Image, validKey("runners.image")); err != nil {
+			return err
+		}
+	}
+	if f.labels == nil {
+		labels := strings.Join(cfg.Runners.Labels, ",")
+		err := p.ask("Labels", &labels, func(s string) error {
+			cfg.Runners.Labels = trimAll(strings.Split(s, ","))
+			return config.ValidateKey(cfg, "runners.labels")
+		})
+		if err != nil {
+			return err
+		}
+	}
+	validGroup := func(group string) error { return checkInitGroup(ctx, cfg, group) }
+	if f.group == "" {
+		if err := p.ask("Runner group", &cfg.Runners.Group, validGroup); err != nil {
+			return err
+		}
+	} else if err := validGroup(f.group); err != nil {
+		return fmt.Errorf("invalid --group: %w", err)
+	}
+	if f.prefix == "" {
+		if err := p.ask("Name prefix", &cfg.Runners.NamePrefix, validKey("runners.name_prefix")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkInitTarget looks up the org, repo or enterprise and, for org scope,
+// the runner group with the GitHub API.
+func checkInitTarget(ctx context.Context, cfg *config.Config) error {
+	// The token may come from a .env file imported above.
+	config.LoadDotenv(config.DotenvPath())
+	token, err := config.ResolveToken(cfg.Token)
+	if err != nil {
+		return err
+	}
+	gh, err := ghclient.NewClient(ctx, token, cfg.GitHub.APIURL(), cfg.GitHub.UploadAPIURL())
+	if err != nil {
+		return err
+	}
+
+	switch cfg.Scope {
+	case "repo":
+		return gh.CheckRepo(ctx, cfg.Repo.Owner, cfg.Repo.Name)
+	case "enterprise":
+		// Enterprises cannot be looked up by slug; listing their runners
+		// checks the slug and the token's access together.
+		_, err := gh.ListEnterpriseRunners(ctx, cfg.Enterprise)
+		return err
+	}
+	if err := gh.CheckOrg(ctx, cfg.Org); err != nil {
+		return err
+	}
+	if cfg.Runners.Group == "" {
+		// Runners join the org's default group.
+		return nil
+	}
+	_, err = gh.FindRunnerGroup(ctx, cfg.Org, cfg.Runners.Group)
+	return err
+}
+
+// checkInitGroup looks up an org's runner group with the GitHub API when the
+// token resolves, so a mistyped group is caught before runners fail to
+// register. Only a missing group is an error: other failures, such as an
+// unknown org or a network error, are printed and left to --check.
+func checkInitGroup(ctx context.Context, cfg *config.Config, group string) error {
+	if cfg.Scope != "org" || group == "" {
+		return nil
+	}
+	config.LoadDotenv(config.DotenvPath())
+	token, err := config.ResolveToken(cfg.Token)
+	if err != nil || token == "" {
+		return nil
+	}
+	gh, err := ghclient.NewClient(ctx, token, cfg.GitHub.APIURL(), cfg.GitHub.UploadAPIURL())
+	if err != nil {
+		return nil
+	}
+	_, err = gh.FindRunnerGroup(ctx, cfg.Org, group)
+	var notFound *ghclient.GroupNotFoundError
+	if errors.As(err, &notFound) {
+		return err
+	}
+	if err != nil {
+		fmt.Printf("Warning: could not check runner group: %v\n", err)
+	}
+	return nil
+}
+
+// prompter reads answers from stdin. With yes set it never prompts and
+// every question takes its default.
+type prompter struct {
+	reader *bufio.Reader
+	yes    bool
+}
+
+// ask prompts for a value, showing the current value as the default, until
+// validate accepts the answer. validate may be nil.
+func (p *prompter) ask(label string, value *string, validate func(string) error) error {
+	if validate == nil {
+		validate = func(string) error { return nil }
+	}
+	if p.yes {
+		if err := validate(*value); err != nil {
+			return fmt.Errorf("%s: %w", strings.ToLower(label), err)
+		}
+		return nil
+	}
+
+	def := *value
+	for {
+		fmt.Printf("%s [%s]: ", label, def)
+		line, readErr := p.reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		*value = answer
+		err := validate(answer)
+		if err == nil {
+			return nil
+		}
+		fmt.Printf("  %s: %v\n", label, err)
+		if errors.Is(readErr, io.EOF) {
+			return fmt.Errorf("%s: %w", strings.ToLower(label), err)
+		}
+	}
+}
+
+// confirm asks a yes/no question. With yes set, or on an empty answer, def
+// is returned.
+func (p *prompter) confirm(question string, def bool) bool {
+	if p.yes {
+		return def
+	}
+	choices := "[y/N]"
+	if def {
+		choices = "[Y/n]"
+	}
+	fmt.Printf("%s %s ", question, choices)
+	switch strings.ToLower(readLine(p.reader)) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func trimAll(items []string) []string {
+	out := make([]string, len(items))
+	for i, s := range items {
+		out[i] = strings.TrimSpace(s)
+	}
+	return out
+}
+
 // findEnvFile returns the first .env file found, checking ~/.ghr/.env then ./.env.
 func findEnvFile() string {
 	for _, p := range []string{config.DotenvPath(), ".env"} {
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/github/githubtest"
+)
+
+func TestPromptConfigChecksGroup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GHR_TEST_UNSET_TOKEN", "")
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddGroup("myorg", "Default")
+
+	flags := initFlags{org: "myorg", tokenRef: "ghp_test", image: "runner:latest", labels: []string{"dev"}, prefix: "ghr"}
+	tests := []struct {
+		name      string
+		token     string
+		group     string // --group
+		yes       bool
+		input     string
+		wantGroup string
+		wantErr   bool
+	}{
+		{name: "missing group asked again", token: "ghp_test", input: "gpu\nDefault\n", wantGroup: "Default"},
+		{name: "missing group at EOF", token: "ghp_test", input: "gpu", wantErr: true},
+		{name: "missing group with --yes", token: "ghp_test", group: "gpu", yes: true, wantErr: true},
+		{name: "existing group with --yes", token: "ghp_test", group: "Default", yes: true, wantGroup: "Default"},
+		{name: "no token, not checked", token: "env:GHR_TEST_UNSET_TOKEN", input: "gpu\n", wantGroup: "gpu"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := flags
+			f.tokenRef = tt.token
+			f.group = tt.group
+			c := config.Default()
+			c.GitHub.BaseURL = srv.URL
+			if err := f.apply(c); err != nil {
+				t.Fatal(err)
+			}
+			p := &prompter{reader: bufio.NewReader(strings.NewReader(tt.input)), yes: tt.yes}
+			var err error
+			captureStdout(t, func() { err = promptConfig(context.Background(), p, c, f) })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("promptConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && c.Runners.Group != tt.wantGroup {
+				t.Errorf("runners.group = %q, want %q", c.Runners.Group, tt.wantGroup)
+			}
+		})
+	}
+}
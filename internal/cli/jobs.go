@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/logs"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
+)
+
+func newJobsCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "jobs [NAME_OR_NUMBER...]",
+		Short: "Show job history parsed from runner logs",
+		Long: `Show which jobs each runner has executed, parsed from the runner
+container logs. Without arguments, all managed runners are included.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targets, err := mgr.Select(cmd.Context(), runner.Selector{All: len(args) == 0, Refs: args})
+			if err != nil {
+				return err
+			}
+			if len(targets) == 0 {
+				fmt.Println("No managed runners found.")
+				return nil
+			}
+
+			opts := docker.LogOptions{Tail: "all", Timestamps: true, Since: since}
+			var lines []logs.Line
+			for _, t := range targets {
+				err := readLogs(cmd.Context(), t, opts, func(l logs.Line) {
+					lines = append(lines, l)
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			jobs := logs.ParseJobs(lines)
+			if len(jobs) == 0 {
+				fmt.Println("No jobs found.")
+				return nil
+			}
+			output.PrintJobTable(os.Stdout, jobs, time.Now())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "only consider logs since timestamp or relative duration (e.g. 24h)")
+	return cmd
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
+)
+
+func newLabelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label",
+		Short: "Change the labels of running runners",
+		Long: `Change the custom labels of registered runners through the GitHub API,
+without recreating their containers. Jobs that ask for the new labels can
+run on the runners at once.
+
+Runners are given by name, number or number range; the first argument that
+is not a runner starts the labels. A label that looks like a runner, such as
+2, 1-3 or ghr-runner-1, must follow --: everything after -- is a label. Docker cannot change the labels of an
+existing container, so the change is recorded in ~/.ghr/runner-labels.yaml
+and shown by ` + "`ghr list`" + `. It lasts until the container is replaced, e.g.
+by ` + "`ghr down`" + ` and ` + "`ghr up`" + `, which registers the runner with
+runners.labels again; records of removed containers are then dropped.`,
+	}
+
+	cmd.AddCommand(
+		newLabelOpCmd("add", "Add labels to runners", 1),
+		newLabelOpCmd("remove", "Remove labels from runners", 1),
+		newLabelOpCmd("set", "Replace the custom labels of runners", 0),
+	)
+	return cmd
+}
+
+// newLabelOpCmd returns the label subcommand for op, which needs at least
+// minLabels labels.
+func newLabelOpCmd(op, short string, minLabels int) *cobra.Command {
+	use := op + " RUNNER... [--] LABEL..."
+	if minLabels == 0 {
+		use = op + " RUNNER... [--] [LABEL...]"
+	}
+	var repo string
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			m, err := repoManager(repo)
+			if err != nil {
+				return err
+			}
+			all, err := m.Select(ctx, runner.Selector{All: true})
+			if err != nil {
+				return err
+			}
+			prefix := m.Config.Runners.NamePrefix
+			refs, labels := runner.SplitRefs(all, args, prefix)
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				refs, labels = args[:dash], args[dash:]
+			}
+			if len(refs) == 0 {
+				return fmt.Errorf("runner %q not found; give runners before labels", args[0])
+			}
+			if len(labels) < minLabels {
+				return fmt.Errorf("no labels given; put -- before labels that look like runners, e.g. %s 3 -- 2", op)
+			}
+			targets, err := runner.Selector{Refs: refs}.Match(all, prefix)
+			if err != nil {
+				return err
+			}
+
+			ghc, err := newGitHubClient(ctx)
+			if err != nil {
+				return err
+			}
+			registered, err := listGitHubRunners(ctx, ghc)
+			if err != nil {
+				return err
+			}
+			ids := make(map[string]int64, len(registered))
+			for _, r := range registered {
+				ids[r.Name] = r.ID
+			}
+			records, err := config.LoadRunnerLabels()
+			if err != nil {
+				return err
+			}
+
+			var (
+				errs    []error
+				changed bool
+			)
+			for _, c := range targets {
+				id, ok := ids[c.Name]
+				if !ok {
+					errs = append(errs, fmt.Errorf("%s: not registered with GitHub", c.Name))
+					continue
+				}
+				owner := runnerOwner(c)
+				now, err := changeLabels(ctx, ghc, op, owner, id, labels)
+				if now != nil {
+					// A remove that failed part-way still changed the labels
+					// removed before the failure.
+					records.Record(c.ID, config.RunnerLabelsEntry{
+						Name:   c.Name,
+						Scope:  cfg.Scope,
+						Target: owner.Org + owner.Repo + owner.Enterprise,
+						Labels: now,
+					})
+					changed = true
+				}
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+					continue
+				}
+				fmt.Printf("%s: %s\n", c.Name, orNone(now))
+			}
+			if m.PruneRunnerLabels(ctx, records) {
+				changed = true
+			}
+			if changed {
+				if err := records.Save(); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+	cmd.Flags().StringVar(&repo, "repo", "", "only runners of this repo from repos:, as OWNER/NAME")
+	return cmd
+}
+
+// changeLabels applies a label operation to a registered runner and returns
+// its custom labels afterwards. Labels are removed one at a time; if one
+// fails, the labels after the last successful removal are returned with the
+// error, or nil if none succeeded.
+func changeLabels(ctx context.Context, ghc *ghclient.Client, op string, owner ghclient.RunnerOwner, id int64, labels []string) ([]string, error) {
+	switch op {
+	case "add":
+		return ghc.AddRunnerLabels(ctx, owner, id, labels)
+	case "set":
+		return ghc.SetRunnerLabels(ctx, owner, id, labels)
+	}
+	var now []string
+	for _, l := range labels {
+		after, err := ghc.RemoveRunnerLabel(ctx, owner, id, l)
+		if err != nil {
+			return now, err
+		}
+		now = after
+	}
+	return now, nil
+}
+
+// runnerOwner returns the org, repository or enterprise the runner in c is
+// registered with.
+func runnerOwner(c docker.RunnerContainer) ghclient.RunnerOwner {
+	switch cfg.Scope {
+	case "org":
+		return ghclient.RunnerOwner{Org: cfg.Org}
+	case "enterprise":
+		return ghclient.RunnerOwner{Enterprise: cfg.Enterprise}
+	}
+	if name := c.Labels[docker.LabelRepoName]; name != "" {
+		return ghclient.RunnerOwner{Repo: c.Labels[docker.LabelRepoOwner] + "/" + name}
+	}
+	return ghclient.RunnerOwner{Repo: cfg.Repo.FullName()}
+}
+
+func orNone(labels []string) string {
+	if len(labels) == 0 {
+		return "(no custom labels)"
+	}
+	return strings.Join(labels, ", ")
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/github/githubtest"
+)
+
+func TestLabelDashSeparatesLabels(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	ids := map[int]int64{
+		2: srv.AddRunner("myorg", "ghr-runner-2", "online", false, "self-hosted"),
+		3: srv.AddRunner("myorg", "ghr-runner-3", "online", false, "self-hosted"),
+	}
+	c := testOrgConfig()
+	c.GitHub.BaseURL = srv.URL
+	useFakeHost(t, c, 2, 3)
+
+	// Without --, a numeric label is taken as a runner.
+	cmd := newLabelOpCmd("add", "", 1)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	cmd.SetArgs([]string{"3", "2"})
+	var err error
+	captureStdout(t, func() { err = cmd.Execute() })
+	if err == nil || !strings.Contains(err.Error(), "no labels given") {
+		t.Fatalf("label add 3 2 error = %v, want no labels given", err)
+	}
+
+	cmd = newLabelOpCmd("add", "", 1)
+	cmd.SetArgs([]string{"3", "--", "2", "1-3"})
+	captureStdout(t, func() { err = cmd.Execute() })
+	if err != nil {
+		t.Fatalf("label add 3 -- 2 1-3 error = %v", err)
+	}
+	if got, want := srv.Labels("myorg", ids[3]), []string{"self-hosted", "2", "1-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ghr-runner-3 labels = %v, want %v", got, want)
+	}
+	if got, want := srv.Labels("myorg", ids[2]), []string{"self-hosted"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ghr-runner-2 labels = %v, want %v", got, want)
+	}
+}
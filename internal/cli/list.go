@@ -6,8 +6,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
-	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
 	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
 )
 
 func newListCmd() *cobra.Command {
@@ -27,40 +27,36 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
-			if showGitHub {
-				token, err := config.ResolveToken(cfg.Token)
-				if err != nil {
-					return fmt.Errorf("resolving token for GitHub API: %w", err)
+			records, err := config.LoadRunnerLabels()
+			if err != nil {
+				return err
+			}
+			if mgr.PruneRunnerLabels(cmd.Context(), records) {
+				if err := records.Save(); err != nil {
+					return err
 				}
-				ghc := ghclient.NewClient(cmd.Context(), token)
+			}
+			runner.ApplyRunnerLabels(runners, records)
 
-				var ghRunners []ghclient.RunnerStatus
-				if cfg.Scope == "org" {
-					ghRunners, err = ghc.ListOrgRunners(cmd.Context(), cfg.Org)
-				} else {
-					ghRunners, err = ghc.ListRepoRunners(cmd.Context(), cfg.Repo.Owner, cfg.Repo.Name)
+			if showGitHub {
+				ghc, err := newGitHubClient(cmd.Context())
+				if err != nil {
+					return err
 				}
+				ghRunners, err := listGitHubRunners(cmd.Context(), ghc)
 				if err != nil {
 					return fmt.Errorf("fetching GitHub runner status: %w", err)
 				}
 
-				// Build lookup by name
-				ghByName := make(map[string]ghclient.RunnerStatus)
-				for _, gr := range ghRunners {
-					ghByName[gr.Name] = gr
-				}
-
-				// Merge
-				for i := range runners {
-					if gr, ok := ghByName[runners[i].Name]; ok {
-						runners[i].GitHubID = gr.ID
-						runners[i].GitHubStatus = gr.Status
-						runners[i].Busy = gr.Busy
-					}
-				}
+				runner.MergeGitHubStatus(runners, ghRunners)
 			}
 
 			output.PrintRunnerTable(os.Stdout, runners, showGitHub)
+			for _, r := range runners {
+				if r.LabelsDrift {
+					fmt.Fprintf(os.Stderr, "Warning: %s has other labels on GitHub than ghr label recorded; run `ghr label set %s LABEL...` to set them again\n", r.Name, r.Name)
+				}
+			}
 			return nil
 		},
 	}
@@ -1,55 +1,207 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/logs"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
 )
 
 func newLogsCmd() *cobra.Command {
-	var follow bool
+	var (
+		all       bool
+		repo      string
+		labelArgs []string
+		job       string
+		opts      docker.LogOptions
+	)
 
 	cmd := &cobra.Command{
-		Use:   "logs NAME_OR_NUMBER",
+		Use:   "logs [NAME_OR_NUMBER...]",
 		Short: "Show runner container logs",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show logs for one or more runners.
+
+Runners can be selected by name, number, number range (e.g. 2-5) or
+container ID prefix, by Docker label with --label, or all at once with
+--all. When more than one runner is selected, lines are prefixed with
+the runner name and interleaved by timestamp.
+
+Use --job to show only the section of output for a named job, from its
+"Running job" line through its completion line.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Resolve name: could be a number, name, or container ID prefix
-			nameOrNum := args[0]
+			if err := validateTail(opts.Tail); err != nil {
+				return err
+			}
 
-			// Find the container to get its full name
-			runners, err := mgr.List(cmd.Context())
+			labels, err := runner.ParseLabelSelectors(labelArgs)
 			if err != nil {
 				return err
 			}
-
-			var containerName string
-			for _, r := range runners {
-				if r.Name == nameOrNum ||
-					fmt.Sprintf("%d", r.Num) == nameOrNum ||
-					r.ContainerID == nameOrNum {
-					containerName = r.Name
-					break
-				}
+			sel := runner.Selector{All: all, Refs: args, Labels: labels}
+			if sel.Empty() {
+				return fmt.Errorf("specify a runner, a --label selector, or use --all")
 			}
-			if containerName == "" {
-				// Try using it directly as a container name/ID
-				containerName = nameOrNum
+
+			var filter *logs.JobFilter
+			if job != "" {
+				filter = logs.NewJobFilter(job)
 			}
 
-			reader, err := dockerCli.ContainerLogs(cmd.Context(), containerName, follow)
+			m, err := repoManager(repo)
 			if err != nil {
-				return fmt.Errorf("getting logs for %s: %w", containerName, err)
+				return err
 			}
-			defer reader.Close()
-
-			_, err = io.Copy(os.Stdout, reader)
-			return err
+			// A single ref is shown without name prefixes when it resolves
+			// to one runner; one that names no managed runner is tried as a
+			// container name or ID.
+			single := len(args) == 1 && !all && len(labels) == 0 && filter == nil
+			targets, err := m.Select(cmd.Context(), sel)
+			var ambiguous *runner.AmbiguousRefError
+			switch {
+			case err != nil && single && !errors.As(err, &ambiguous):
+				return singleRunnerLogs(cmd.Context(), "", args[0], opts)
+			case err != nil:
+				return err
+			case len(targets) == 0:
+				fmt.Println("No managed runners found.")
+				return nil
+			case single && len(targets) == 1:
+				return singleRunnerLogs(cmd.Context(), targets[0].Host, targets[0].Name, opts)
+			}
+			return aggregateLogs(cmd.Context(), targets, opts, filter)
 		},
 	}
 
-	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "follow log output")
+	cmd.Flags().BoolVarP(&opts.Follow, "follow", "f", false, "follow log output")
+	cmd.Flags().BoolVar(&all, "all", false, "show logs from all managed runners")
+	cmd.Flags().StringVar(&repo, "repo", "", "only runners of this repo from repos:, as OWNER/NAME")
+	cmd.Flags().StringArrayVarP(&labelArgs, "label", "l", nil, "select runners by Docker label KEY=VALUE (repeatable)")
+	cmd.Flags().StringVarP(&opts.Tail, "tail", "n", "100", `number of lines to show from the end of the logs, or "all"`)
+	cmd.Flags().StringVar(&opts.Since, "since", "", "show logs since timestamp (e.g. 2024-01-02T13:23:37Z) or relative (e.g. 42m)")
+	cmd.Flags().StringVar(&opts.Until, "until", "", "show logs before timestamp (e.g. 2024-01-02T13:23:37Z) or relative (e.g. 42m)")
+	cmd.Flags().BoolVarP(&opts.Timestamps, "timestamps", "t", false, "show timestamps")
+	cmd.Flags().StringVar(&job, "job", "", "only show output from the job with this name")
 	return cmd
 }
+
+func validateTail(tail string) error {
+	if tail == "all" {
+		return nil
+	}
+	if n, err := strconv.Atoi(tail); err != nil || n < 0 {
+		return fmt.Errorf(`invalid --tail value %q; expected a non-negative number or "all"`, tail)
+	}
+	return nil
+}
+
+// singleRunnerLogs copies the logs of one container on the given host as
+// they are.
+func singleRunnerLogs(ctx context.Context, host, containerName string, opts docker.LogOptions) error {
+	dc := mgr.Client(host)
+	tty, err := dc.ContainerTTY(ctx, containerName)
+	if err != nil {
+		return err
+	}
+	reader, err := dc.ContainerLogs(ctx, containerName, opts)
+	if err != nil {
+		return fmt.Errorf("getting logs for %s: %w", containerName, err)
+	}
+	defer reader.Close()
+
+	return logs.Copy(os.Stdout, os.Stderr, reader, tty)
+}
+
+// aggregateLogs prints the recent history of every target merged by
+// timestamp, then, when following, streams new lines as they arrive.
+// Timestamps are always requested so lines can be ordered; they are only
+// printed when opts.Timestamps is set. A non-nil filter drops lines outside
+// the selected job.
+func aggregateLogs(ctx context.Context, targets []docker.RunnerContainer, opts docker.LogOptions, filter *logs.JobFilter) error {
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.Name
+	}
+	printer := logs.NewPrinter(os.Stdout, os.Stderr, names, logs.ColorEnabled(os.Stdout))
+	printer.Timestamps = opts.Timestamps
+	emit := func(l logs.Line) {
+		if filter == nil || filter.Keep(l) {
+			printer.Print(l)
+		}
+	}
+
+	start := time.Now()
+	history := opts
+	history.Follow = false
+	history.Timestamps = true
+
+	var lines []logs.Line
+	last := make(map[string]time.Time)
+	for _, t := range targets {
+		err := readLogs(ctx, t, history, func(l logs.Line) {
+			lines = append(lines, l)
+			if !l.Time.IsZero() {
+				last[l.Runner] = l.Time
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	logs.SortByTime(lines)
+	for _, l := range lines {
+		emit(l)
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	live := opts
+	live.Timestamps = true
+	live.Since = fmt.Sprintf("%d.%09d", start.Unix(), start.Nanosecond())
+	var wg sync.WaitGroup
+	errs := make(chan error, len(targets))
+	for _, t := range targets {
+		wg.Add(1)
+		go func(t docker.RunnerContainer) {
+			defer wg.Done()
+			errs <- readLogs(ctx, t, live, func(l logs.Line) {
+				// Skip lines already printed as part of the history. Lines
+				// without a timestamp cannot be matched and are kept.
+				if !l.Time.IsZero() && !l.Time.After(last[t.Name]) {
+					return
+				}
+				emit(l)
+			})
+		}(t)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readLogs(ctx context.Context, t docker.RunnerContainer, opts docker.LogOptions, fn func(logs.Line)) error {
+	dc := mgr.Client(t.Host)
+	tty, err := dc.ContainerTTY(ctx, t.Name)
+	if err != nil {
+		return err
+	}
+	reader, err := dc.ContainerLogs(ctx, t.Name, opts)
+	if err != nil {
+		return fmt.Errorf("getting logs for %s: %w", t.Name, err)
+	}
+	defer reader.Close()
+	return logs.Read(t.Name, reader, tty, fn)
+}
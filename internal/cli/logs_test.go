@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogsRange(t *testing.T) {
+	f := useFakeHost(t, testOrgConfig(), 1, 2, 3)
+	f.Logs = map[string]string{
+		"ghr-runner-1": "2024-01-02T13:00:00Z from one\n",
+		"ghr-runner-2": "2024-01-02T13:00:01Z from two\n",
+		"ghr-runner-3": "2024-01-02T13:00:02Z from three\n",
+	}
+
+	tests := []struct {
+		args       []string
+		want, skip []string
+	}{
+		{[]string{"2-3"}, []string{"from two", "from three"}, []string{"from one"}},
+		{[]string{"2"}, []string{"from two"}, []string{"from one", "from three"}},
+	}
+	for _, tt := range tests {
+		cmd := newLogsCmd()
+		cmd.SetArgs(tt.args)
+		var err error
+		out := captureStdout(t, func() { err = cmd.Execute() })
+		if err != nil {
+			t.Fatalf("logs %v error = %v", tt.args, err)
+		}
+		for _, w := range tt.want {
+			if !strings.Contains(out, w) {
+				t.Errorf("logs %v output = %q, want %q", tt.args, out, w)
+			}
+		}
+		for _, s := range tt.skip {
+			if strings.Contains(out, s) {
+				t.Errorf("logs %v output = %q, want no %q", tt.args, out, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
+)
+
+// repoManager returns the manager for the runners of repo, given as
+// "owner/name" from repos:, or mgr when repo is empty.
+func repoManager(repo string) (*runner.Manager, error) {
+	if repo == "" {
+		return mgr, nil
+	}
+	pools, err := mgr.Pools(repo)
+	if err != nil {
+		return nil, err
+	}
+	return pools[0], nil
+}
+
+// forEachPool runs fn with the manager of each selected repo in repos:, or
+// with mgr when the config has no repos. Each repo's output is headed by its
+// name, and a failing repo does not stop the others.
+func forEachPool(repos []string, fn func(p *runner.Manager) error) error {
+	pools, err := mgr.Pools(repos...)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Repos) == 0 {
+		return fn(pools[0])
+	}
+	var errs []error
+	for i, p := range pools {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", p.Config.Repo.FullName())
+		if err := fn(p); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Config.Repo.FullName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/report"
+)
+
+func newReportCmd() *cobra.Command {
+	var (
+		since  string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report [NAME_OR_NUMBER...]",
+		Short: "Show runner utilization and capacity report",
+		Long: `Compute per-runner and fleet-wide utilization from GitHub job timestamps:
+busy time percentage, queue wait (job created to started), jobs per hour
+and peak concurrency over the --since window.
+
+Fleet busy percentage is busy time divided by (runners x window), where
+runners are the current managed containers plus any runner that ran a job
+in the window.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "csv" && format != "json" {
+				return fmt.Errorf("invalid output format %q; must be table, csv or json", format)
+			}
+			window, err := parseWindow(since)
+			if err != nil {
+				return err
+			}
+
+			match, err := runnerNameMatcher(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
+			containers, err := mgr.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			var names []string
+			for _, c := range containers {
+				if match(c.Name) {
+					names = append(names, c.Name)
+				}
+			}
+
+			end := time.Now()
+			start := end.Add(-window)
+			ghc, err := newGitHubClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			all, err := listGitHubJobs(cmd.Context(), ghc, start)
+			if err != nil {
+				return fmt.Errorf("fetching workflow jobs: %w", err)
+			}
+			jobs := all[:0]
+			for _, j := range all {
+				if match(j.RunnerName) {
+					jobs = append(jobs, j)
+				}
+			}
+
+			r := report.Compute(jobs, names, start, end)
+			switch format {
+			case "csv":
+				return output.WriteReportCSV(os.Stdout, r)
+			case "json":
+				return output.WriteReportJSON(os.Stdout, r)
+			}
+			output.PrintReportTable(os.Stdout, r)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "30d", "report window (e.g. 24h, 7d, 30d)")
+	cmd.Flags().StringVarP(&format, "output", "o", "table", "output format: table, csv or json")
+	return cmd
+}
@@ -13,9 +13,11 @@ import (
 var (
 	Version    = "dev"
 	cfgFile    string
+	cfgSets    []string
+	cfgContext string
 	cfg        *config.Config
 	cfgPath    string
-	dockerCli  *docker.Client
+	cfgLoaded  *config.Loaded
 	mgr        *runner.Manager
 )
 
@@ -23,12 +25,15 @@ var (
 // that should run without loading config or connecting to Docker.
 func skipConfigLoad(cmd *cobra.Command) bool {
 	skip := map[string]bool{
-		"init":               true,
-		"version":            true,
-		"completion":         true,
-		"help":               true,
-		"__complete":         true,
-		"__completeNoDesc":   true,
+		"init":             true,
+		"config":           true,
+		"doctor":           true,
+		"context":          true,
+		"version":          true,
+		"completion":       true,
+		"help":             true,
+		"__complete":       true,
+		"__completeNoDesc": true,
 	}
 	for c := cmd; c != nil; c = c.Parent() {
 		if skip[c.Name()] {
@@ -38,6 +43,62 @@ func skipConfigLoad(cmd *cobra.Command) bool {
 	return false
 }
 
+// skipDocker returns true if the command (or any of its parents) needs the
+// config but only talks to the GitHub API, so no Docker host is connected.
+func skipDocker(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "groups" {
+			return true
+		}
+	}
+	return false
+}
+
+// activeContext returns the context named by --context, or the current
+// context, or nil if neither is set.
+func activeContext() (*config.Context, error) {
+	contexts, err := config.LoadContexts()
+	if err != nil {
+		return nil, err
+	}
+	return contexts.Active(cfgContext)
+}
+
+// loadConfig loads the config layers from path, or the discovered files if
+// path is empty, for the active context.
+func loadConfig(path string) (*config.Loaded, error) {
+	c, err := activeContext()
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadContext(c, path, cfgSets)
+}
+
+// connectHosts creates a Docker client for each host in the config, or for
+// the docker: endpoint if no hosts are listed.
+func connectHosts(cfg *config.Config) ([]*runner.Host, error) {
+	if len(cfg.Hosts) == 0 {
+		dc, err := docker.NewClient(cfg.Docker)
+		if err != nil {
+			return nil, err
+		}
+		return []*runner.Host{{Runtime: dc}}, nil
+	}
+
+	var hosts []*runner.Host
+	for _, h := range cfg.Hosts {
+		dc, err := docker.NewClient(h.DockerConf(cfg.Docker))
+		if err != nil {
+			for _, prev := range hosts {
+				prev.Runtime.Close()
+			}
+			return nil, fmt.Errorf("host %s: %w", h.Name, err)
+		}
+		hosts = append(hosts, &runner.Host{Name: h.Name, Runtime: dc, Weight: h.Weight, MaxRunners: h.MaxRunners, Socket: h.Socket})
+	}
+	return hosts, nil
+}
+
 func NewRootCmd() *cobra.Command {
 	root := &cobra.Command{
 		Use:   "ghr",
@@ -53,24 +114,28 @@ func NewRootCmd() *cobra.Command {
 			}
 
 			var err error
-			cfg, cfgPath, err = config.Load(cfgFile)
+			cfgLoaded, err = loadConfig(cfgFile)
 			if err != nil {
 				return err
 			}
-			if err := config.Validate(cfg); err != nil {
+			cfg, cfgPath = cfgLoaded.Config, cfgLoaded.Path
+			if err := cfgLoaded.Validate(); err != nil {
 				return fmt.Errorf("invalid config: %w", err)
 			}
+			if skipDocker(cmd) {
+				return nil
+			}
 
-			dockerCli, err = docker.NewClient(cfg.Docker.Socket)
+			hosts, err := connectHosts(cfg)
 			if err != nil {
 				return err
 			}
-			mgr = runner.NewManager(cfg, dockerCli)
+			mgr = runner.NewManager(cfg, hosts)
 			return nil
 		},
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
-			if dockerCli != nil {
-				dockerCli.Close()
+			if mgr != nil {
+				mgr.Close()
 			}
 		},
 		SilenceUsage: true,
@@ -79,7 +144,9 @@ func NewRootCmd() *cobra.Command {
 		},
 	}
 
-	root.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ~/.ghr/config.yaml)")
+	root.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: merge /etc/ghr, ~/.config/ghr, ~/.ghr and ./.ghr.yaml)")
+	root.PersistentFlags().StringVar(&cfgContext, "context", "", "named context to use (default: the current context; see `ghr context`)")
+	root.PersistentFlags().StringArrayVar(&cfgSets, "set", nil, "override a config value, e.g. --set runners.count=12 (repeatable)")
 
 	root.AddCommand(
 		newCompletionCmd(),
@@ -89,10 +156,19 @@ func NewRootCmd() *cobra.Command {
 		newScaleCmd(),
 		newListCmd(),
 		newLogsCmd(),
+		newJobsCmd(),
+		newHistoryCmd(),
+		newGroupsCmd(),
+		newReportCmd(),
 		newStopCmd(),
 		newStartCmd(),
 		newRmCmd(),
+		newLabelCmd(),
 		newStatusCmd(),
+		newConfigCmd(),
+		newDoctorCmd(),
+		newContextCmd(),
+		newScheduleCmd(),
 		newVersionCmd(),
 	)
 
@@ -5,19 +5,31 @@ import (
 	"strconv"
 
 	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
 )
 
 func newScaleCmd() *cobra.Command {
-	return &cobra.Command{
+	var repos []string
+
+	cmd := &cobra.Command{
 		Use:   "scale COUNT",
 		Short: "Scale to exactly COUNT runners",
-		Args:  cobra.ExactArgs(1),
+		Long: `Scale to exactly COUNT runners.
+
+With a repos: list, each repo is scaled to COUNT runners; --repo limits this
+to the given repos.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			target, err := strconv.Atoi(args[0])
 			if err != nil || target < 0 {
 				return fmt.Errorf("invalid count: %s", args[0])
 			}
-			return mgr.Scale(cmd.Context(), target)
+			return forEachPool(repos, func(p *runner.Manager) error {
+				return p.Scale(cmd.Context(), target)
+			})
 		},
 	}
+
+	cmd.Flags().StringSliceVar(&repos, "repo", nil, "only these repos from repos:, as OWNER/NAME (repeatable)")
+	return cmd
 }
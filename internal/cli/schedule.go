@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/schedule"
+)
+
+func newScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Scale runners on a cron schedule",
+		Long: `Scale runners according to the schedules: section of the config.
+
+Each schedule sets the target runner count from the time its cron
+expression fires until another schedule fires. With a repos: list, the
+count applies to each repo: a count of 4 with two repos runs 8 runners.`,
+	}
+
+	cmd.AddCommand(
+		newScheduleRunCmd(),
+		newScheduleNextCmd(),
+	)
+	return cmd
+}
+
+func newScheduleRunCmd() *cobra.Command {
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the scheduler in the foreground",
+		Long: `Apply the current scheduled count, then keep scaling at every transition
+until interrupted. Between transitions the runner count is re-checked every
+--interval so runners removed outside ghr are replaced.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := scheduleEntries()
+			if err != nil {
+				return err
+			}
+			if interval <= 0 {
+				return fmt.Errorf("invalid interval: %s", interval)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runSchedule(ctx, entries, interval)
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Minute, "how often to reconcile the runner count between transitions")
+	return cmd
+}
+
+func newScheduleNextCmd() *cobra.Command {
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "next",
+		Short: "Show upcoming schedule transitions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := scheduleEntries()
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if tr, ok := schedule.Current(entries, now); ok {
+				fmt.Printf("Current: %d runners (%s, since %s)\n\n",
+					tr.Entry.Count, tr.Entry.Name, tr.At.Format("2006-01-02 15:04 MST"))
+			}
+			output.PrintTransitionTable(os.Stdout, schedule.Upcoming(entries, now, count))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&count, "count", "n", 10, "number of transitions to show")
+	return cmd
+}
+
+func scheduleEntries() ([]schedule.Entry, error) {
+	if len(cfg.Schedules) == 0 {
+		return nil, fmt.Errorf("no schedules configured; add a schedules: section to %s", cfgPath)
+	}
+	entries := make([]schedule.Entry, 0, len(cfg.Schedules))
+	for i, s := range cfg.Schedules {
+		e, err := s.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("schedules[%d]: %w", i, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// runSchedule scales to the current target, then sleeps until the next
+// transition or reconcile interval, whichever comes first.
+func runSchedule(ctx context.Context, entries []schedule.Entry, interval time.Duration) error {
+	pools, err := mgr.Pools()
+	if err != nil {
+		return err
+	}
+
+	var announced time.Time
+	for {
+		now := time.Now()
+		if tr, ok := schedule.Current(entries, now); ok {
+			applySchedule(ctx, pools, tr.Entry)
+		}
+
+		wait := interval
+		if next := schedule.Upcoming(entries, now, 1); len(next) > 0 {
+			if d := time.Until(next[0].At); d < wait {
+				wait = d
+			}
+			if !next[0].At.Equal(announced) {
+				announced = next[0].At
+				logf("Next transition: %s -> %d runners at %s",
+					next[0].Entry.Name, next[0].Entry.Count, next[0].At.Format("2006-01-02 15:04 MST"))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logf("Scheduler stopped.")
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// applySchedule scales each pool to the entry's count. With repos:, the
+// count applies to every repo, not to their total. Failures are logged so
+// the scheduler keeps running.
+func applySchedule(ctx context.Context, pools []*runner.Manager, e schedule.Entry) {
+	for _, p := range pools {
+		target := ""
+		if len(cfg.Repos) > 0 {
+			target = " " + p.Config.Repo.FullName()
+		}
+		runners, err := p.List(ctx)
+		if err != nil {
+			logf("Warning:%s %v", target, err)
+		} else if len(runners) != e.Count {
+			logf("Schedule %q: scaling%s to %d runners", e.Name, target, e.Count)
+			if err := p.Scale(ctx, e.Count); err != nil {
+				logf("Warning:%s %v", target, err)
+			}
+		}
+	}
+}
+
+// logf prints a scheduler message prefixed with the current time.
+func logf(format string, a ...any) {
+	fmt.Printf("%s "+format+"\n", append([]any{time.Now().Format(time.RFC3339)}, a...)...)
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/schedule"
+)
+
+func TestApplyScheduleRepos(t *testing.T) {
+	c := config.Default()
+	c.Scope = "repo"
+	c.Token = "ghp_test"
+	c.Repos = []config.RepoPoolConf{{Owner: "me", Name: "app", Count: 1}, {Owner: "me", Name: "web", Count: 5}}
+	f := useFakeHost(t, c)
+	pools, err := mgr.Pools()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	captureStdout(t, func() {
+		applySchedule(context.Background(), pools, schedule.Entry{Name: "workday", Count: 2})
+	})
+
+	// The schedule's count applies to each repo, overriding repos[].count.
+	perRepo := make(map[string]int)
+	for _, ctr := range f.Containers() {
+		perRepo[ctr.Labels[docker.LabelRepoName]]++
+	}
+	if perRepo["app"] != 2 || perRepo["web"] != 2 || len(perRepo) != 2 {
+		t.Errorf("runners per repo = %v, want 2 for app and web", perRepo)
+	}
+}
@@ -7,7 +7,10 @@ import (
 )
 
 func newStartCmd() *cobra.Command {
-	var all bool
+	var (
+		all  bool
+		repo string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "start [NAME_OR_NUMBER]",
@@ -20,10 +23,15 @@ func newStartCmd() *cobra.Command {
 			if len(args) > 0 {
 				nameOrNum = args[0]
 			}
-			return mgr.Start(cmd.Context(), nameOrNum, all)
+			m, err := repoManager(repo)
+			if err != nil {
+				return err
+			}
+			return m.Start(cmd.Context(), nameOrNum, all)
 		},
 	}
 
 	cmd.Flags().BoolVar(&all, "all", false, "start all managed runners")
+	cmd.Flags().StringVar(&repo, "repo", "", "only runners of this repo from repos:, as OWNER/NAME")
 	return cmd
 }
@@ -1,10 +1,15 @@
 package cli
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
 	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
 )
 
 func newStatusCmd() *cobra.Command {
@@ -27,12 +32,47 @@ func newStatusCmd() *cobra.Command {
 				}
 			}
 
-			output.PrintStatusSummary(os.Stdout, cfgPath,
-				cfg.Scope, cfg.Org, cfg.Repo.Owner, cfg.Repo.Name,
+			output.PrintStatusSummary(os.Stdout, strings.Join(cfgLoaded.Files, ", "),
+				cfg.GitHub.WebURL(), cfg.Scope, cfg.Target(),
 				cfg.Runners.Image, cfg.Runners.Labels,
 				len(runners), running, stopped,
 			)
+
+			if mgr.Fleet() {
+				fmt.Println()
+				output.PrintHostTable(os.Stdout, hostRows(runners))
+			}
+
+			entries := cfgLoaded.Entries()
+			for i := range entries {
+				if entries[i].Key == "token" {
+					entries[i].Value = config.RedactToken(cfg.Token)
+				}
+			}
+			fmt.Println()
+			output.PrintConfigSources(os.Stdout, entries)
 			return nil
 		},
 	}
 }
+
+// hostRows counts the runners on each fleet host.
+func hostRows(runners []runner.RunnerInfo) []output.HostRow {
+	rows := make([]output.HostRow, len(cfg.Hosts))
+	for i, h := range cfg.Hosts {
+		rows[i] = output.HostRow{Name: h.Name, Endpoint: "-", Weight: h.Weight, MaxRunners: h.MaxRunners}
+		if ep, err := docker.ResolveEndpoint(h.DockerConf(cfg.Docker)); err == nil {
+			rows[i].Endpoint = ep.Host
+		}
+		for _, r := range runners {
+			switch {
+			case r.Host != h.Name:
+			case r.DockerState == "running":
+				rows[i].Running++
+			default:
+				rows[i].Stopped++
+			}
+		}
+	}
+	return rows
+}
@@ -7,7 +7,10 @@ import (
 )
 
 func newStopCmd() *cobra.Command {
-	var all bool
+	var (
+		all  bool
+		repo string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "stop [NAME_OR_NUMBER]",
@@ -20,10 +23,15 @@ func newStopCmd() *cobra.Command {
 			if len(args) > 0 {
 				nameOrNum = args[0]
 			}
-			return mgr.Stop(cmd.Context(), nameOrNum, all)
+			m, err := repoManager(repo)
+			if err != nil {
+				return err
+			}
+			return m.Stop(cmd.Context(), nameOrNum, all)
 		},
 	}
 
 	cmd.Flags().BoolVar(&all, "all", false, "stop all managed runners")
+	cmd.Flags().StringVar(&repo, "repo", "", "only runners of this repo from repos:, as OWNER/NAME")
 	return cmd
 }
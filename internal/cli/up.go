@@ -2,19 +2,33 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/output"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
 )
 
 func newUpCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		repos  []string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "up [COUNT]",
 		Short: "Create and start runners",
-		Long:  "Create and start COUNT new runners (additive). Defaults to runners.count from config.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Create and start COUNT new runners (additive). Defaults to runners.count from config.
+
+With a repos: list, each repo gets its own count of runners, or COUNT each;
+--repo limits this to the given repos.
+
+With --dry-run, the runners are listed with their names, labels and extra
+environment, templates rendered, instead of being created.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			count := cfg.Runners.Count
+			count := 0
 			if len(args) == 1 {
 				var err error
 				count, err = strconv.Atoi(args[0])
@@ -23,12 +37,36 @@ func newUpCmd() *cobra.Command {
 				}
 			}
 
-			created, err := mgr.Up(cmd.Context(), count)
-			if err != nil {
+			if !dryRun {
+				warnPodmanRestart(cmd.Context())
+			}
+			total := 0
+			err := forEachPool(repos, func(p *runner.Manager) error {
+				n := count
+				if n == 0 {
+					n = p.Config.Runners.Count
+				}
+				if dryRun {
+					plan, err := p.Plan(cmd.Context(), n)
+					if err != nil {
+						return err
+					}
+					output.PrintPlanTable(os.Stdout, plan)
+					return nil
+				}
+				created, err := p.Up(cmd.Context(), n)
+				total += len(created)
+				return err
+			})
+			if err != nil || dryRun {
 				return err
 			}
-			fmt.Printf("\n%d runner(s) created.\n", len(created))
+			fmt.Printf("\n%d runner(s) created.\n", total)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringSliceVar(&repos, "repo", nil, "only these repos from repos:, as OWNER/NAME (repeatable)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the runners that would be created, with templates rendered")
+	return cmd
 }
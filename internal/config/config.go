@@ -2,19 +2,32 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/lamtuanvu/gh-runner-ctl/internal/schedule"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	Version int        `yaml:"version"`
 	Scope   string     `yaml:"scope"`
 	Org     string     `yaml:"org,omitempty"`
 	Repo    RepoConfig `yaml:"repo,omitempty"`
-	Token   string     `yaml:"token"`
-	Runners RunnerConf `yaml:"runners"`
-	Docker  DockerConf `yaml:"docker"`
+	// Repos lists several repositories for repo scope, each served by its
+	// own runners; it replaces Repo.
+	Repos []RepoPoolConf `yaml:"repos,omitempty"`
+	// Enterprise is the enterprise slug, required when Scope is "enterprise".
+	Enterprise string         `yaml:"enterprise,omitempty"`
+	Token      string         `yaml:"token"`
+	GitHub     GitHubConf     `yaml:"github,omitempty"`
+	Runners    RunnerConf     `yaml:"runners"`
+	Docker     DockerConf     `yaml:"docker"`
+	Hosts      []HostConf     `yaml:"hosts,omitempty"`
+	Schedules  []ScheduleConf `yaml:"schedules,omitempty"`
 }
 
 type RepoConfig struct {
@@ -22,6 +35,146 @@ type RepoConfig struct {
 	Name  string `yaml:"name"`
 }
 
+// FullName returns "owner/name".
+func (r RepoConfig) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// RepoPoolConf is one repository in repos:. Count defaults to
+// runners.count, NamePrefix to "<runners.name_prefix>-<name>", and Labels
+// are added to runners.labels.
+type RepoPoolConf struct {
+	Owner      string   `yaml:"owner"`
+	Name       string   `yaml:"name"`
+	Count      int      `yaml:"count,omitempty"`
+	NamePrefix string   `yaml:"name_prefix,omitempty"`
+	Labels     []string `yaml:"labels,omitempty"`
+}
+
+// FullName returns "owner/name".
+func (r RepoPoolConf) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// Target returns what runners register with for the configured scope: the
+// org name, the enterprise slug or "owner/name", or the repos in repos:
+// separated by commas.
+func (c *Config) Target() string {
+	switch c.Scope {
+	case "org":
+		return c.Org
+	case "enterprise":
+		return c.Enterprise
+	}
+	if len(c.Repos) > 0 {
+		names := make([]string, len(c.Repos))
+		for i, r := range c.Repos {
+			names[i] = r.FullName()
+		}
+		return strings.Join(names, ", ")
+	}
+	return c.Repo.FullName()
+}
+
+// Pools returns a config for each repo in repos:, with Repo set and the
+// repo's count, name prefix and labels applied to runners:, or c alone when
+// repos is empty.
+func (c *Config) Pools() []*Config {
+	if len(c.Repos) == 0 {
+		return []*Config{c}
+	}
+	pools := make([]*Config, len(c.Repos))
+	for i, r := range c.Repos {
+		p := *c
+		p.Repos = nil
+		p.Repo = RepoConfig{Owner: r.Owner, Name: r.Name}
+		if r.Count != 0 {
+			p.Runners.Count = r.Count
+		}
+		p.Runners.NamePrefix = r.PoolPrefix(c.Runners.NamePrefix)
+		p.Runners.Labels = append(append([]string(nil), c.Runners.Labels...), r.Labels...)
+		pools[i] = &p
+	}
+	return pools
+}
+
+// PoolPrefix returns the repo's name prefix: NamePrefix, or base and the
+// repo name joined by "-".
+func (r RepoPoolConf) PoolPrefix(base string) string {
+	if r.NamePrefix != "" {
+		return r.NamePrefix
+	}
+	return base + "-" + r.Name
+}
+
+// GitHubConf points ghr at a GitHub Enterprise Server instance instead of
+// github.com. BaseURL is the instance URL, e.g. https://ghe.example.com;
+// UploadURL is only needed when uploads are served from another host.
+type GitHubConf struct {
+	BaseURL   string `yaml:"base_url,omitempty"`
+	UploadURL string `yaml:"upload_url,omitempty"`
+}
+
+// APIURL returns the REST API root, e.g. https://ghe.example.com/api/v3/,
+// or "" for github.com.
+func (g GitHubConf) APIURL() string {
+	return apiRoot(g.BaseURL, "api/v3/")
+}
+
+// UploadAPIURL returns the upload API root for UploadURL, or for BaseURL
+// when UploadURL is empty, or "" for github.com.
+func (g GitHubConf) UploadAPIURL() string {
+	if g.UploadURL != "" {
+		return apiRoot(g.UploadURL, "api/uploads/")
+	}
+	base := strings.TrimSuffix(strings.TrimSuffix(g.BaseURL, "/"), "/api/v3")
+	return apiRoot(base, "api/uploads/")
+}
+
+// apiRoot appends suffix to raw unless it already ends with it or names an
+// api. host, the same way go-github treats enterprise URLs.
+func apiRoot(raw, suffix string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	if !strings.HasSuffix(u.Path, "/"+suffix) && !strings.HasPrefix(u.Host, "api.") && !strings.Contains(u.Host, ".api.") {
+		u.Path += suffix
+	}
+	return u.String()
+}
+
+// WebURL returns the URL runners register against, without a trailing
+// slash: https://github.com, or BaseURL without an /api/v3 suffix or api.
+// host prefix.
+func (g GitHubConf) WebURL() string {
+	if g.BaseURL == "" {
+		return "https://github.com"
+	}
+	u, err := url.Parse(g.BaseURL)
+	if err != nil {
+		return strings.TrimSuffix(g.BaseURL, "/")
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/api/v3")
+	u.Host = strings.TrimPrefix(u.Host, "api.")
+	return strings.TrimSuffix(u.String(), "/")
+}
+
+// WebHost returns the host (and port) of WebURL.
+func (g GitHubConf) WebHost() string {
+	u, err := url.Parse(g.WebURL())
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
 type RunnerConf struct {
 	Count      int               `yaml:"count"`
 	Image      string            `yaml:"image"`
@@ -33,10 +186,63 @@ type RunnerConf struct {
 }
 
 type DockerConf struct {
-	Socket            string `yaml:"socket"`
-	MountDockerSocket bool   `yaml:"mount_docker_socket"`
-	RestartPolicy     string `yaml:"restart_policy"`
-	WorkDirBase       string `yaml:"work_dir_base,omitempty"`
+	Host              string        `yaml:"host,omitempty"`
+	Context           string        `yaml:"context,omitempty"`
+	TLS               DockerTLSConf `yaml:"tls,omitempty"`
+	Socket            string        `yaml:"socket"`
+	MountDockerSocket bool          `yaml:"mount_docker_socket"`
+	RestartPolicy     string        `yaml:"restart_policy"`
+	WorkDirBase       string        `yaml:"work_dir_base,omitempty"`
+}
+
+// DockerTLSConf holds the client certificate files for a tcp:// Docker host.
+// Without a CA file the system roots are used.
+type DockerTLSConf struct {
+	CA         string `yaml:"ca,omitempty"`
+	Cert       string `yaml:"cert,omitempty"`
+	Key        string `yaml:"key,omitempty"`
+	SkipVerify bool   `yaml:"skip_verify,omitempty"`
+}
+
+// Enabled reports whether any TLS setting is given.
+func (t DockerTLSConf) Enabled() bool {
+	return t != DockerTLSConf{}
+}
+
+// HostConf is one Docker host of a fleet. Runners are spread over the hosts
+// in proportion to Weight (default 1), never exceeding MaxRunners on a host
+// (0 means no limit). Host, Context, TLS and Socket replace the matching
+// docker: settings for this host; the rest of docker: applies to every host.
+type HostConf struct {
+	Name       string        `yaml:"name"`
+	Host       string        `yaml:"host,omitempty"`
+	Context    string        `yaml:"context,omitempty"`
+	TLS        DockerTLSConf `yaml:"tls,omitempty"`
+	Socket     string        `yaml:"socket,omitempty"`
+	Weight     int           `yaml:"weight,omitempty"`
+	MaxRunners int           `yaml:"max_runners,omitempty"`
+}
+
+// DockerConf returns the docker settings for the host: base with the host's
+// endpoint and socket applied.
+func (h HostConf) DockerConf(base DockerConf) DockerConf {
+	if h.Host != "" || h.Context != "" {
+		base.Host, base.Context, base.TLS = h.Host, h.Context, h.TLS
+	}
+	if h.Socket != "" {
+		base.Socket = h.Socket
+	}
+	return base
+}
+
+// ScheduleConf sets the target runner count from each time Cron fires until
+// the next schedule fires. Timezone is an IANA name such as "Europe/Berlin"
+// and must be given explicitly.
+type ScheduleConf struct {
+	Name     string `yaml:"name,omitempty"`
+	Cron     string `yaml:"cron"`
+	Count    int    `yaml:"count"`
+	Timezone string `yaml:"timezone"`
 }
 
 // Dir returns the ghr config directory (~/.ghr).
@@ -60,8 +266,9 @@ func DotenvPath() string {
 
 func Default() *Config {
 	return &Config{
-		Scope: "org",
-		Token: "env:GH_TOKEN",
+		Version: CurrentVersion,
+		Scope:   "org",
+		Token:   "env:GH_TOKEN",
 		Runners: RunnerConf{
 			Count:      10,
 			Image:      "myoung34/github-runner:latest",
@@ -78,38 +285,14 @@ func Default() *Config {
 	}
 }
 
-// searchPaths returns config file paths in priority order.
-func searchPaths() []string {
-	return []string{
-		DefaultConfigPath(),
-	}
-}
-
-// Load reads config from the given path, or searches default locations.
+// Load reads config from the given path, or merges the discovered config
+// layers, and returns the config with the path edits should be written to.
 func Load(path string) (*Config, string, error) {
-	if path != "" {
-		cfg, err := loadFrom(path)
-		return cfg, path, err
-	}
-	for _, p := range searchPaths() {
-		if _, err := os.Stat(p); err == nil {
-			cfg, err := loadFrom(p)
-			return cfg, p, err
-		}
-	}
-	return nil, "", fmt.Errorf("no config file found; run `ghr init` to create one")
-}
-
-func loadFrom(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	loaded, err := LoadLayers(path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("reading config: %w", err)
-	}
-	cfg := Default()
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("parsing config: %w", err)
+		return nil, "", err
 	}
-	return cfg, nil
+	return loaded.Config, loaded.Path, nil
 }
 
 // Save writes the config to the given path.
@@ -125,27 +308,25 @@ func Save(cfg *Config, path string) error {
 	return os.WriteFile(path, data, 0o644)
 }
 
-// Validate checks required fields.
-func Validate(cfg *Config) error {
-	if cfg.Scope != "org" && cfg.Scope != "repo" {
-		return fmt.Errorf("scope must be 'org' or 'repo', got %q", cfg.Scope)
+// Parse converts the schedule into an evaluable entry.
+func (s ScheduleConf) Parse() (schedule.Entry, error) {
+	if s.Timezone == "" {
+		return schedule.Entry{}, fmt.Errorf("timezone is required")
 	}
-	if cfg.Scope == "org" && cfg.Org == "" {
-		return fmt.Errorf("org name is required when scope is 'org'")
-	}
-	if cfg.Scope == "repo" {
-		if cfg.Repo.Owner == "" || cfg.Repo.Name == "" {
-			return fmt.Errorf("repo owner and name are required when scope is 'repo'")
-		}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return schedule.Entry{}, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
 	}
-	if cfg.Token == "" {
-		return fmt.Errorf("token is required")
+	if s.Count < 0 {
+		return schedule.Entry{}, fmt.Errorf("count must not be negative, got %d", s.Count)
 	}
-	if cfg.Runners.Image == "" {
-		return fmt.Errorf("runner image is required")
+	c, err := schedule.ParseCron(s.Cron, loc)
+	if err != nil {
+		return schedule.Entry{}, err
 	}
-	if cfg.Runners.NamePrefix == "" {
-		return fmt.Errorf("runner name_prefix is required")
+	name := s.Name
+	if name == "" {
+		name = s.Cron
 	}
-	return nil
+	return schedule.Entry{Name: name, Count: s.Count, Cron: c}, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -38,6 +39,155 @@ func TestValidate(t *testing.T) {
 			c.Repo = RepoConfig{Owner: "owner"}
 		}, true},
 		{"missing token", func(c *Config) { c.Org = "myorg"; c.Token = "" }, true},
+		{"valid repos config", func(c *Config) {
+			c.Scope = "repo"
+			c.Repos = []RepoPoolConf{{Owner: "me", Name: "app", Count: 2}, {Owner: "me", Name: "web", Labels: []string{"web"}}}
+		}, false},
+		{"repos with repo", func(c *Config) {
+			c.Scope = "repo"
+			c.Repo = RepoConfig{Owner: "owner", Name: "repo"}
+			c.Repos = []RepoPoolConf{{Owner: "me", Name: "app"}}
+		}, true},
+		{"repos with org scope", func(c *Config) {
+			c.Org = "myorg"
+			c.Repos = []RepoPoolConf{{Owner: "me", Name: "app"}}
+		}, true},
+		{"repos with the same prefix", func(c *Config) {
+			c.Scope = "repo"
+			c.Repos = []RepoPoolConf{{Owner: "me", Name: "app"}, {Owner: "you", Name: "app"}}
+		}, true},
+		{"repo label repeats runners.labels", func(c *Config) {
+			c.Scope = "repo"
+			c.Repos = []RepoPoolConf{{Owner: "me", Name: "app", Labels: []string{"Local"}}}
+		}, true},
+		{"templates", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.NamePrefix = "ci-{{.Host}}"
+			c.Runners.Labels = []string{"linux", `{{if eq (mod .Num 2) 1}}arm-emulated{{end}}`}
+			c.Runners.ExtraEnv = map[string]string{"CACHE_DIR": "/cache/{{.Num}}"}
+		}, false},
+		{"template with unknown field", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.ExtraEnv = map[string]string{"CACHE_DIR": "/cache/{{.Number}}"}
+		}, true},
+		{"template that does not parse", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.Labels = []string{"{{.Num"}
+		}, true},
+		{"name prefix template renders invalid name", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.NamePrefix = "{{.Host}}"
+		}, true},
+		{"repo name prefix template", func(c *Config) {
+			c.Scope = "repo"
+			c.Runners.NamePrefix = "ci-{{.Pool}}"
+			c.Repos = []RepoPoolConf{{Owner: "me", Name: "app"}, {Owner: "me", Name: "web", NamePrefix: "{{.Pool}}-{{.Num"}}
+		}, true},
+		{"valid enterprise config", func(c *Config) {
+			c.Scope = "enterprise"
+			c.Enterprise = "acme"
+		}, false},
+		{"enterprise missing slug", func(c *Config) { c.Scope = "enterprise" }, true},
+		{"github base url", func(c *Config) {
+			c.Org = "myorg"
+			c.GitHub.BaseURL = "https://ghe.example.com"
+		}, false},
+		{"github base url without scheme", func(c *Config) {
+			c.Org = "myorg"
+			c.GitHub.BaseURL = "ghe.example.com"
+		}, true},
+		{"github upload url without base url", func(c *Config) {
+			c.Org = "myorg"
+			c.GitHub.UploadURL = "https://uploads.example.com"
+		}, true},
+		{"valid schedule", func(c *Config) {
+			c.Org = "myorg"
+			c.Schedules = []ScheduleConf{{Cron: "0 8 * * 1-5", Count: 20, Timezone: "UTC"}}
+		}, false},
+		{"schedule missing timezone", func(c *Config) {
+			c.Org = "myorg"
+			c.Schedules = []ScheduleConf{{Cron: "0 8 * * 1-5", Count: 20}}
+		}, true},
+		{"schedule bad cron", func(c *Config) {
+			c.Org = "myorg"
+			c.Schedules = []ScheduleConf{{Cron: "0 25 * * *", Count: 20, Timezone: "UTC"}}
+		}, true},
+		{"invalid restart policy", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.RestartPolicy = "sometimes"
+		}, true},
+		{"docker host", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.Host = "tcp://build:2375"
+		}, false},
+		{"docker host without scheme", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.Host = "/var/run/docker.sock"
+		}, true},
+		{"ssh docker host", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.Host = "ssh://ci@build"
+		}, false},
+		{"tls with ssh host", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.Host = "ssh://ci@build"
+			c.Docker.TLS.SkipVerify = true
+		}, true},
+		{"tls cert without key", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.Host = "tcp://build:2376"
+			c.Docker.TLS.Cert = os.Args[0]
+		}, true},
+		{"hosts", func(c *Config) {
+			c.Org = "myorg"
+			c.Hosts = []HostConf{{Name: "build-1", Host: "tcp://build-1:2375"}, {Name: "build-2", Weight: 2, MaxRunners: 10}}
+		}, false},
+		{"duplicate host names", func(c *Config) {
+			c.Org = "myorg"
+			c.Hosts = []HostConf{{Name: "build"}, {Name: "build"}}
+		}, true},
+		{"host without name", func(c *Config) {
+			c.Org = "myorg"
+			c.Hosts = []HostConf{{Host: "tcp://build:2375"}}
+		}, true},
+		{"negative host weight", func(c *Config) {
+			c.Org = "myorg"
+			c.Hosts = []HostConf{{Name: "build", Weight: -1}}
+		}, true},
+		{"invalid host endpoint", func(c *Config) {
+			c.Org = "myorg"
+			c.Hosts = []HostConf{{Name: "build", Host: "build:2375"}}
+		}, true},
+		{"tls files missing until connecting", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.Host = "tcp://build:2376"
+			c.Docker.TLS.CA = "/nonexistent/ca.pem"
+		}, false},
+		{"label with comma", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.Labels = []string{"linux,x64"}
+		}, true},
+		{"duplicate label", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.Labels = []string{"gpu", "GPU"}
+		}, true},
+		{"invalid name prefix", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.NamePrefix = "my runners"
+		}, true},
+		{"relative work dir", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.WorkDirBase = "work"
+		}, true},
+		{"missing work dir on the Docker host", func(c *Config) {
+			c.Org = "myorg"
+			c.Docker.Host = "ssh://builder@build1"
+			c.Docker.WorkDirBase = "/nonexistent/ghr-work"
+		}, false},
+		{"reserved extra env", func(c *Config) {
+			c.Org = "myorg"
+			c.Runners.ExtraEnv = map[string]string{"RUNNER_NAME": "x"}
+		}, true},
 	}
 
 	for _, tt := range tests {
@@ -52,6 +202,69 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestPools(t *testing.T) {
+	cfg := Default()
+	if pools := cfg.Pools(); len(pools) != 1 || pools[0] != cfg {
+		t.Fatalf("Pools() without repos = %v, want the config itself", pools)
+	}
+
+	cfg.Scope = "repo"
+	cfg.Repos = []RepoPoolConf{
+		{Owner: "me", Name: "app", Count: 2, Labels: []string{"app"}},
+		{Owner: "me", Name: "web", NamePrefix: "www"},
+	}
+	pools := cfg.Pools()
+	if len(pools) != 2 {
+		t.Fatalf("Pools() returned %d configs, want 2", len(pools))
+	}
+	app, web := pools[0], pools[1]
+	if app.Repo.FullName() != "me/app" || app.Runners.Count != 2 || app.Runners.NamePrefix != "ghr-app" {
+		t.Errorf("app pool = %+v, %+v", app.Repo, app.Runners)
+	}
+	if want := []string{"local", "dev", "app"}; !reflect.DeepEqual(app.Runners.Labels, want) {
+		t.Errorf("app labels = %v, want %v", app.Runners.Labels, want)
+	}
+	if web.Runners.Count != cfg.Runners.Count || web.Runners.NamePrefix != "www" || len(web.Repos) != 0 {
+		t.Errorf("web pool = %+v", web)
+	}
+	if want := []string{"local", "dev"}; !reflect.DeepEqual(cfg.Runners.Labels, want) {
+		t.Errorf("base labels changed to %v", cfg.Runners.Labels)
+	}
+	if got := cfg.Target(); got != "me/app, me/web" {
+		t.Errorf("Target() = %q", got)
+	}
+}
+
+func TestGitHubConfURLs(t *testing.T) {
+	tests := []struct {
+		conf                   GitHubConf
+		api, upload, web, host string
+	}{
+		{GitHubConf{}, "", "", "https://github.com", "github.com"},
+		{GitHubConf{BaseURL: "https://ghe.example.com"},
+			"https://ghe.example.com/api/v3/", "https://ghe.example.com/api/uploads/", "https://ghe.example.com", "ghe.example.com"},
+		{GitHubConf{BaseURL: "https://ghe.example.com/api/v3/"},
+			"https://ghe.example.com/api/v3/", "https://ghe.example.com/api/uploads/", "https://ghe.example.com", "ghe.example.com"},
+		{GitHubConf{BaseURL: "https://api.ghe.example.com:8443", UploadURL: "https://uploads.example.com"},
+			"https://api.ghe.example.com:8443/", "https://uploads.example.com/api/uploads/", "https://ghe.example.com:8443", "ghe.example.com:8443"},
+	}
+	for _, tt := range tests {
+		c := tt.conf
+		if got := c.APIURL(); got != tt.api {
+			t.Errorf("%+v APIURL() = %q, want %q", c, got, tt.api)
+		}
+		if got := c.UploadAPIURL(); got != tt.upload {
+			t.Errorf("%+v UploadAPIURL() = %q, want %q", c, got, tt.upload)
+		}
+		if got := c.WebURL(); got != tt.web {
+			t.Errorf("%+v WebURL() = %q, want %q", c, got, tt.web)
+		}
+		if got := c.WebHost(); got != tt.host {
+			t.Errorf("%+v WebHost() = %q, want %q", c, got, tt.host)
+		}
+	}
+}
+
 func TestSaveAndLoad(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "test.yaml")
@@ -224,3 +437,16 @@ func TestResolveToken(t *testing.T) {
 		}
 	})
 }
+
+func TestHostDockerConf(t *testing.T) {
+	base := DockerConf{Host: "tcp://default:2375", Socket: "/var/run/docker.sock", RestartPolicy: "always"}
+
+	got := HostConf{Name: "a", Context: "build"}.DockerConf(base)
+	if got.Host != "" || got.Context != "build" || got.Socket != base.Socket || got.RestartPolicy != "always" {
+		t.Errorf("DockerConf() with context = %+v", got)
+	}
+	got = HostConf{Name: "b", Socket: "/run/docker.sock"}.DockerConf(base)
+	if got.Host != base.Host || got.Socket != "/run/docker.sock" {
+		t.Errorf("DockerConf() with socket = %+v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Context is a named bundle of a config file, Docker endpoint and token,
+// selected with `ghr context use` or --context. Empty fields leave the
+// corresponding setting to the normal config layers.
+type Context struct {
+	Name       string `yaml:"-"`
+	Config     string `yaml:"config,omitempty"`
+	DockerHost string `yaml:"docker_host,omitempty"`
+	Token      string `yaml:"token,omitempty"`
+}
+
+// Contexts is the contents of the contexts file.
+type Contexts struct {
+	Current  string             `yaml:"current,omitempty"`
+	Contexts map[string]Context `yaml:"contexts"`
+}
+
+// ContextsPath returns the path of the contexts file (~/.ghr/contexts.yaml).
+func ContextsPath() string {
+	return filepath.Join(Dir(), "contexts.yaml")
+}
+
+// LoadContexts reads the contexts file. A missing file yields no contexts.
+func LoadContexts() (*Contexts, error) {
+	cs := &Contexts{Contexts: make(map[string]Context)}
+	data, err := os.ReadFile(ContextsPath())
+	if os.IsNotExist(err) {
+		return cs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading contexts: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cs); err != nil {
+		return nil, fmt.Errorf("%s: parsing contexts: %w", ContextsPath(), err)
+	}
+	if cs.Contexts == nil {
+		cs.Contexts = make(map[string]Context)
+	}
+	return cs, nil
+}
+
+// Save writes the contexts file. It may hold literal tokens, so it is only
+// readable by the owner.
+func (cs *Contexts) Save() error {
+	data, err := yaml.Marshal(cs)
+	if err != nil {
+		return fmt.Errorf("marshaling contexts: %w", err)
+	}
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	return os.WriteFile(ContextsPath(), data, 0o600)
+}
+
+// Get returns the named context.
+func (cs *Contexts) Get(name string) (*Context, error) {
+	c, ok := cs.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found; see `ghr context list`", name)
+	}
+	c.Name = name
+	return &c, nil
+}
+
+// List returns all contexts sorted by name.
+func (cs *Contexts) List() []Context {
+	list := make([]Context, 0, len(cs.Contexts))
+	for name, c := range cs.Contexts {
+		c.Name = name
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Active returns the context named by override, or the current context if
+// override is empty. It returns nil if neither is set.
+func (cs *Contexts) Active(override string) (*Context, error) {
+	name := override
+	if name == "" {
+		name = cs.Current
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return cs.Get(name)
+}
+
+// source is the origin reported for values set by the context.
+func (c *Context) source() string {
+	return "context " + c.Name
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestContextsSaveLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cs, err := LoadContexts()
+	if err != nil {
+		t.Fatalf("LoadContexts() error = %v", err)
+	}
+	if c, err := cs.Active(""); c != nil || err != nil {
+		t.Errorf("Active() = %v, %v; want nil, nil with no contexts", c, err)
+	}
+
+	cs.Contexts["work"] = Context{Config: "/etc/work.yaml", Token: "env:WORK_TOKEN"}
+	cs.Contexts["home"] = Context{DockerHost: "tcp://nas:2375"}
+	cs.Current = "work"
+	if err := cs.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	info, err := os.Stat(ContextsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("contexts file mode = %o, want 600", perm)
+	}
+
+	cs, err = LoadContexts()
+	if err != nil {
+		t.Fatalf("LoadContexts() error = %v", err)
+	}
+	list := cs.List()
+	if len(list) != 2 || list[0].Name != "home" || list[1].Name != "work" {
+		t.Errorf("List() = %v", list)
+	}
+	c, err := cs.Active("")
+	if err != nil || c.Name != "work" || c.Config != "/etc/work.yaml" {
+		t.Errorf("Active(\"\") = %v, %v; want work", c, err)
+	}
+	if c, err := cs.Active("home"); err != nil || c.DockerHost != "tcp://nas:2375" {
+		t.Errorf("Active(home) = %v, %v", c, err)
+	}
+	if _, err := cs.Active("nope"); err == nil {
+		t.Error("Active(nope) expected error")
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	setupLayers(t)
+	t.Setenv("GHR_TOKEN", "env:FROM_ENV")
+	path := filepath.Join(t.TempDir(), "work.yaml")
+	if err := os.WriteFile(path, []byte("org: work-org\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := &Context{Name: "work", Config: path, DockerHost: "tcp://build:2375", Token: "env:WORK_TOKEN"}
+
+	loaded, err := LoadContext(c, "", []string{"docker.host=unix:///tmp/d.sock"})
+	if err != nil {
+		t.Fatalf("LoadContext() error = %v", err)
+	}
+	if len(loaded.Files) != 1 || loaded.Files[0] != path {
+		t.Errorf("Files = %v, want only %s", loaded.Files, path)
+	}
+	if loaded.Config.Org != "work-org" {
+		t.Errorf("org = %q, want work-org", loaded.Config.Org)
+	}
+	if loaded.Config.Token != "env:WORK_TOKEN" || loaded.Origin("token") != "context work" {
+		t.Errorf("token = %q from %q, want the context's", loaded.Config.Token, loaded.Origin("token"))
+	}
+	if loaded.Config.Docker.Host != "unix:///tmp/d.sock" || loaded.Origin("docker.host") != "flag --set" {
+		t.Errorf("docker.host = %q from %q, want the --set value", loaded.Config.Docker.Host, loaded.Origin("docker.host"))
+	}
+
+	// An explicit --config wins over the context's file.
+	_, user, _ := setupLayers(t)
+	loaded, err = LoadContext(c, user, nil)
+	if err != nil {
+		t.Fatalf("LoadContext() error = %v", err)
+	}
+	if loaded.Path != user || loaded.Config.Org != "user-org" {
+		t.Errorf("Path = %q, org = %q; want %s, user-org", loaded.Path, loaded.Config.Org, user)
+	}
+}
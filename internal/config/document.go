@@ -0,0 +1,183 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a config file parsed as a YAML node tree, so it can be edited
+// without losing comments or key order.
+type Document struct {
+	Path   string
+	root   yaml.Node
+	indent int
+}
+
+// OpenDocument parses the config file at path. A missing or empty file
+// yields an empty document.
+func OpenDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	return ParseDocument(path, data)
+}
+
+// ParseDocument parses config file content.
+func ParseDocument(path string, data []byte) (*Document, error) {
+	d := &Document{Path: path, indent: detectIndent(data)}
+	if err := yaml.Unmarshal(data, &d.root); err != nil {
+		return nil, fmt.Errorf("%s: parsing config: %w", path, err)
+	}
+	if len(d.root.Content) == 0 {
+		d.root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if d.root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: config must be a YAML mapping", path)
+	}
+	return d, nil
+}
+
+// Set sets a dotted key, creating parent mappings as needed. An existing
+// value keeps its position and comments.
+func (d *Document) Set(key string, value any) error {
+	parts, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	var val yaml.Node
+	if err := val.Encode(value); err != nil {
+		return fmt.Errorf("encoding %s: %w", key, err)
+	}
+
+	m := d.root.Content[0]
+	for i, p := range parts {
+		last := i == len(parts)-1
+		existing := mappingValue(m, p)
+		if existing == nil {
+			next := &val
+			if !last {
+				next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: p}, next)
+			m = next
+			continue
+		}
+		if last {
+			val.HeadComment = existing.HeadComment
+			val.LineComment = existing.LineComment
+			val.FootComment = existing.FootComment
+			*existing = val
+			return nil
+		}
+		if existing.Kind != yaml.MappingNode {
+			*existing = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", LineComment: existing.LineComment}
+		}
+		m = existing
+	}
+	return nil
+}
+
+// Bytes encodes the document using the file's original indentation.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(d.indent)
+	if err := enc.Encode(&d.root); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes the document back to its path.
+func (d *Document) Save() error {
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	return WriteFile(d.Path, data)
+}
+
+// WriteFile writes config content to path, creating the directory if needed.
+func WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ParseValue parses a command-line value for key according to the key's
+// type, using the same rules as GHR_* environment variables.
+func ParseValue(key, raw string) (any, error) {
+	keys := Keys()
+	if t, ok := keys[key]; ok {
+		if t.Kind() == reflect.Map {
+			pairs, err := parseEnvPairs(raw)
+			if err != nil {
+				return nil, err
+			}
+			m := make(map[string]string, len(pairs))
+			for _, kv := range pairs {
+				m[kv[0]] = kv[1]
+			}
+			return m, nil
+		}
+		return parseEnvValue(t, raw)
+	}
+	if knownKey(key) {
+		return raw, nil // map entry
+	}
+	return nil, fmt.Errorf("unknown config key %q", key)
+}
+
+// splitKey splits a dotted key into YAML path segments. Everything after a
+// map-typed key is a single map entry key, so "runners.extra_env.A.B" is
+// ["runners", "extra_env", "A.B"].
+func splitKey(key string) ([]string, error) {
+	if !knownKey(key) {
+		return nil, fmt.Errorf("unknown config key %q", key)
+	}
+	keys := Keys()
+	if _, ok := keys[key]; ok {
+		return strings.Split(key, "."), nil
+	}
+	for k, t := range keys {
+		if t.Kind() == reflect.Map && strings.HasPrefix(key, k+".") {
+			return append(strings.Split(k, "."), strings.TrimPrefix(key, k+".")), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown config key %q", key)
+}
+
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// detectIndent returns the indentation of the first indented line, or 4
+// (the yaml.Marshal default used by Save) if there is none.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n > 0 {
+			return n
+		}
+	}
+	return 4
+}
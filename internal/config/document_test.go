@@ -0,0 +1,88 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocumentSetKeepsComments(t *testing.T) {
+	src := `# fleet config
+scope: org
+org: my-org # the org
+runners:
+  # how many
+  count: 10
+  image: myoung34/github-runner:latest
+docker:
+  socket: /var/run/docker.sock
+`
+	doc, err := ParseDocument("config.yaml", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+	if err := doc.Set("runners.count", 12); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := doc.Set("runners.extra_env.CACHE.DIR", "/cache"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := doc.Set("docker.work_dir_base", "/srv/work"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := doc.Set("runners.nope", 1); err == nil {
+		t.Error("Set() expected error for unknown key")
+	}
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"# fleet config\n",
+		"org: my-org # the org\n",
+		"  # how many\n  count: 12\n",
+		"  extra_env:\n    CACHE.DIR: /cache\n",
+		"  work_dir_base: /srv/work\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "scope:") > strings.Index(out, "runners:") {
+		t.Errorf("key order changed:\n%s", out)
+	}
+}
+
+func TestDocumentEmpty(t *testing.T) {
+	doc, err := ParseDocument("new.yaml", nil)
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+	if err := doc.Set("runners.labels", []string{"a", "b"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	data, _ := doc.Bytes()
+	if got, want := string(data), "runners:\n    labels:\n        - a\n        - b\n"; got != want {
+		t.Errorf("Bytes() = %q, want %q", got, want)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	if v, err := ParseValue("runners.count", "12"); err != nil || v != 12 {
+		t.Errorf("ParseValue(count) = %v, %v", v, err)
+	}
+	if v, err := ParseValue("runners.ephemeral", "false"); err != nil || v != false {
+		t.Errorf("ParseValue(ephemeral) = %v, %v", v, err)
+	}
+	if _, err := ParseValue("runners.count", "twelve"); err == nil {
+		t.Error("ParseValue(count) expected error for non-integer")
+	}
+	if v, err := ParseValue("runners.extra_env.FOO", "bar"); err != nil || v != "bar" {
+		t.Errorf("ParseValue(extra_env.FOO) = %v, %v", v, err)
+	}
+	if _, err := ParseValue("nope", "x"); err == nil {
+		t.Error("ParseValue() expected error for unknown key")
+	}
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of environment variables that override config keys.
+const EnvPrefix = "GHR_"
+
+// EnvName returns the environment variable that overrides key, e.g.
+// GHR_RUNNERS_IMAGE for "runners.image".
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// applyEnv applies GHR_* environment overrides. Values are parsed according
+// to the key's type:
+//
+//   - strings are used as-is
+//   - ints and bools use strconv parsing
+//   - string lists are comma-separated: GHR_RUNNERS_LABELS=a,b
+//   - string maps are comma-separated KEY=VALUE pairs:
+//     GHR_RUNNERS_EXTRA_ENV=FOO=1,BAR=2; single entries can also be set
+//     with GHR_RUNNERS_EXTRA_ENV_FOO=1
+//   - anything else (e.g. schedules) is parsed as YAML
+func applyEnv(set func(key string, value any, source string)) error {
+	keys := Keys()
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, key := range names {
+		t := keys[key]
+		env := EnvName(key)
+		source := "env " + env
+
+		if t.Kind() == reflect.Map {
+			if raw, ok := os.LookupEnv(env); ok {
+				pairs, err := parseEnvPairs(raw)
+				if err != nil {
+					return fmt.Errorf("%s: %w", env, err)
+				}
+				for _, kv := range pairs {
+					set(key+"."+kv[0], kv[1], source)
+				}
+			}
+			for _, kv := range os.Environ() {
+				name, val, _ := strings.Cut(kv, "=")
+				entry, ok := strings.CutPrefix(name, env+"_")
+				if !ok || entry == "" {
+					continue
+				}
+				set(key+"."+entry, val, "env "+name)
+			}
+			continue
+		}
+
+		raw, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+		value, err := parseEnvValue(t, raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", env, err)
+		}
+		set(key, value, source)
+	}
+	return nil
+}
+
+func parseEnvValue(t reflect.Type, raw string) (any, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", raw)
+		}
+		return n, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", raw)
+		}
+		return b, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			list := []any{}
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			return list, nil
+		}
+	}
+	var value any
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, fmt.Errorf("invalid YAML value: %w", err)
+	}
+	return value, nil
+}
+
+// parseEnvPairs parses "K1=V1,K2=V2".
+func parseEnvPairs(raw string) ([][2]string, error) {
+	var pairs [][2]string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid entry %q; expected KEY=VALUE", item)
+		}
+		pairs = append(pairs, [2]string{k, v})
+	}
+	return pairs, nil
+}
+
+var interpolationRe = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// Interpolate expands ${VAR} and ${VAR:-default} references in s from the
+// environment. In strings with such a reference, "$$" produces a literal
+// "$"; strings without one are returned as they are, so values written
+// before interpolation existed, such as shell snippets, keep their "$$".
+// Referencing an unset variable without a default is an error.
+func Interpolate(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var missing []string
+	out := interpolationRe.ReplaceAllStringFunc(s, func(m string) string {
+		if m == "$$" {
+			return "$"
+		}
+		sub := interpolationRe.FindStringSubmatch(m)
+		if val, ok := os.LookupEnv(sub[1]); ok && (val != "" || sub[2] == "") {
+			return val
+		}
+		if sub[2] != "" {
+			return sub[3]
+		}
+		missing = append(missing, sub[1])
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %q is not set", missing[0])
+	}
+	return out, nil
+}
+
+// interpolateNode expands ${VAR} references in every scalar value of a
+// parsed YAML document. Unquoted values have their type re-resolved, so
+// "count: ${COUNT}" decodes as an integer.
+func interpolateNode(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		if !strings.Contains(n.Value, "$") {
+			return nil
+		}
+		val, err := Interpolate(n.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		n.Value = val
+		if n.Style == 0 {
+			n.Tag = ""
+		}
+		return nil
+	}
+	for i, c := range n.Content {
+		// Keys of mappings are left alone.
+		if n.Kind == yaml.MappingNode && i%2 == 0 {
+			continue
+		}
+		if err := interpolateNode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
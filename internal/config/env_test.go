@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("GHR_TEST_HOST", "build-1")
+	t.Setenv("GHR_TEST_EMPTY", "")
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "plain", false},
+		{"${GHR_TEST_HOST}", "build-1", false},
+		{"ghr-${GHR_TEST_HOST}-x", "ghr-build-1-x", false},
+		{"${GHR_TEST_UNSET:-fallback}", "fallback", false},
+		{"${GHR_TEST_EMPTY:-fallback}", "fallback", false},
+		{"${GHR_TEST_EMPTY}", "", false},
+		{"$$HOME and $HOME", "$$HOME and $HOME", false},
+		{"echo $$ > pid", "echo $$ > pid", false},
+		{"$${GHR_TEST_HOST} is ${GHR_TEST_HOST}", "${GHR_TEST_HOST} is build-1", false},
+		{"${GHR_TEST_UNSET}", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Interpolate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Interpolate(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Interpolate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadLayersInterpolation(t *testing.T) {
+	t.Setenv("TEST_GHR_ORG", "interp-org")
+	t.Setenv("TEST_GHR_COUNT", "7")
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "org: ${TEST_GHR_ORG}\nrunners:\n  count: ${TEST_GHR_COUNT}\n  image: \"${TEST_GHR_IMAGE:-custom/image}\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Org != "interp-org" || cfg.Runners.Count != 7 || cfg.Runners.Image != "custom/image" {
+		t.Errorf("interpolated config = org %q, count %d, image %q", cfg.Org, cfg.Runners.Count, cfg.Runners.Image)
+	}
+
+	if err := os.WriteFile(path, []byte("org: ${TEST_GHR_MISSING}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load(path); err == nil {
+		t.Error("Load() expected error for unset variable")
+	}
+}
+
+func TestEnvOverridesAllTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("org: file-org\nrunners:\n  extra_env:\n    KEEP: file\n    JAVA.OPTS: -Xmx1g\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GHR_RUNNERS_LABELS", "a, b,,c")
+	t.Setenv("GHR_RUNNERS_EPHEMERAL", "false")
+	t.Setenv("GHR_RUNNERS_EXTRA_ENV", "FOO=1,BAR=x=y")
+	t.Setenv("GHR_RUNNERS_EXTRA_ENV_Cache_Dir", "/cache")
+	t.Setenv("GHR_RUNNERS_EXTRA_ENV_com.example.flag", "on")
+	t.Setenv("GHR_DOCKER_MOUNT_DOCKER_SOCKET", "0")
+	t.Setenv("GHR_SCHEDULES", `[{cron: "0 8 * * *", count: 5, timezone: UTC}]`)
+
+	loaded, err := LoadLayers(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	cfg := loaded.Config
+
+	if !reflect.DeepEqual(cfg.Runners.Labels, []string{"a", "b", "c"}) {
+		t.Errorf("labels = %v", cfg.Runners.Labels)
+	}
+	if cfg.Runners.Ephemeral || cfg.Docker.MountDockerSocket {
+		t.Errorf("bools not overridden: ephemeral=%v mount=%v", cfg.Runners.Ephemeral, cfg.Docker.MountDockerSocket)
+	}
+	wantEnv := map[string]string{"KEEP": "file", "JAVA.OPTS": "-Xmx1g", "FOO": "1", "BAR": "x=y", "Cache_Dir": "/cache", "com.example.flag": "on"}
+	if !reflect.DeepEqual(cfg.Runners.ExtraEnv, wantEnv) {
+		t.Errorf("extra_env = %v, want %v", cfg.Runners.ExtraEnv, wantEnv)
+	}
+	if src := loaded.Origin("runners.extra_env.Cache_Dir"); src != "env GHR_RUNNERS_EXTRA_ENV_Cache_Dir" {
+		t.Errorf("extra_env.Cache_Dir origin = %q", src)
+	}
+	if len(cfg.Schedules) != 1 || cfg.Schedules[0].Count != 5 || cfg.Schedules[0].Timezone != "UTC" {
+		t.Errorf("schedules = %+v", cfg.Schedules)
+	}
+
+	t.Setenv("GHR_RUNNERS_EXTRA_ENV", "novalue")
+	if _, err := LoadLayers(path, nil); err == nil {
+		t.Error("LoadLayers() expected error for malformed map entry")
+	}
+}
@@ -0,0 +1,442 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceDefault is the origin reported for values no layer has set.
+const SourceDefault = "default"
+
+// ProjectConfigName is the file name searched for from the working directory
+// upwards for project-local config.
+const ProjectConfigName = ".ghr.yaml"
+
+// systemConfigPath is the system-wide config file; a variable for tests.
+var systemConfigPath = "/etc/ghr/config.yaml"
+
+// Loaded is the result of merging every config layer. Layers apply in the
+// order defaults < system < user < project < GHR_* env vars < context <
+// --set flags.
+type Loaded struct {
+	Config *Config
+	// Path is the highest-priority config file, where edits should go.
+	Path string
+	// Files lists the merged config files, lowest priority first.
+	Files []string
+	// Origins maps dotted keys (e.g. "runners.image") to the layer that set
+	// them. Keys missing from the map come from the defaults.
+	Origins map[string]string
+	// Outdated lists the files older than CurrentVersion. They are migrated
+	// in memory; `ghr config migrate` upgrades them on disk.
+	Outdated []string
+	// Context is the named context the config was loaded for, or nil.
+	Context *Context
+
+	overrides []string
+	positions map[string]Position
+	problems  []Problem
+}
+
+// Origin returns the layer that set key.
+func (l *Loaded) Origin(key string) string {
+	if o, ok := l.Origins[key]; ok {
+		return o
+	}
+	return SourceDefault
+}
+
+// SystemConfigPath returns the system-wide config file path (/etc/ghr/config.yaml).
+func SystemConfigPath() string {
+	return systemConfigPath
+}
+
+// XDGConfigPath returns $XDG_CONFIG_HOME/ghr/config.yaml, falling back to
+// ~/.config when XDG_CONFIG_HOME is unset.
+func XDGConfigPath() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "ghr", "config.yaml")
+}
+
+// ProjectConfigPath returns the nearest .ghr.yaml found by walking up from
+// the working directory, or "" if there is none.
+func ProjectConfigPath() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		p := filepath.Join(dir, ProjectConfigName)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// SearchPaths returns the existing config files in merge order, lowest
+// priority first: system, XDG user, ~/.ghr user, project.
+func SearchPaths() []string {
+	candidates := []string{
+		SystemConfigPath(),
+		XDGConfigPath(),
+		DefaultConfigPath(),
+		ProjectConfigPath(),
+	}
+	var found []string
+	seen := make(map[string]bool)
+	for _, p := range candidates {
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		if _, err := os.Stat(p); err == nil {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// LoadLayers loads and merges all config layers. If path is non-empty it is
+// used instead of the discovered files. Overrides are KEY=VALUE pairs from
+// --set flags, applied last; values are parsed as YAML.
+func LoadLayers(path string, overrides []string) (*Loaded, error) {
+	return LoadContext(nil, path, overrides)
+}
+
+// LoadContext is like LoadLayers for a named context, which may be nil. The
+// context's config file is used instead of the discovered files unless path
+// is given, and its Docker host and token override env vars but not --set.
+func LoadContext(c *Context, path string, overrides []string) (*Loaded, error) {
+	if path == "" && c != nil && c.Config != "" {
+		path = c.Config
+	}
+	files := []string{path}
+	if path == "" {
+		files = SearchPaths()
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no config file found; run `ghr init` to create one")
+		}
+	}
+	return loadLayers(files, c, overrides, nil)
+}
+
+// WithFile re-merges the same layers with data in place of the file at path,
+// which is added as the highest-priority file if it was not already loaded.
+// It is used to check an edit before it is written.
+func (l *Loaded) WithFile(path string, data []byte) (*Loaded, error) {
+	files := l.Files
+	found := false
+	for _, f := range files {
+		if f == path {
+			found = true
+		}
+	}
+	if !found {
+		files = append(append([]string{}, files...), path)
+	}
+	return loadLayers(files, l.Context, l.overrides, map[string][]byte{path: data})
+}
+
+// loadLayers merges files in order, reading content from contents when
+// present and from disk otherwise, then applies env vars, the context c (if
+// not nil) and overrides.
+func loadLayers(files []string, c *Context, overrides []string, contents map[string][]byte) (*Loaded, error) {
+	merged := make(map[string]any)
+	origins := make(map[string]string)
+	positions := make(map[string]Position)
+	var problems []Problem
+	var outdated []string
+	set := func(key string, value any, source string) {
+		setPath(merged, key, value)
+		origins[key] = source
+	}
+
+	for _, f := range files {
+		data, ok := contents[f]
+		if !ok {
+			var err error
+			if data, err = os.ReadFile(f); err != nil {
+				return nil, fmt.Errorf("reading config: %w", err)
+			}
+		}
+		layer, migrated, err := parseLayer(f, data, positions, &problems)
+		if err != nil {
+			return nil, err
+		}
+		if migrated {
+			outdated = append(outdated, f)
+		}
+		for _, kv := range flatten(layer, "") {
+			set(kv.key, kv.value, f)
+		}
+	}
+
+	if err := applyEnv(set); err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		if c.DockerHost != "" {
+			set("docker.host", c.DockerHost, c.source())
+		}
+		if c.Token != "" {
+			set("token", c.Token, c.source())
+		}
+	}
+
+	for _, o := range overrides {
+		key, raw, ok := strings.Cut(o, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %q; expected KEY=VALUE", o)
+		}
+		if !knownKey(key) {
+			return nil, fmt.Errorf("invalid --set %q: unknown config key %q", o, key)
+		}
+		var value any
+		if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, fmt.Errorf("invalid --set %q: %w", o, err)
+		}
+		set(key, value, "flag --set")
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("merging config: %w", err)
+	}
+	cfg := Default()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing merged config: %w", err)
+	}
+
+	return &Loaded{
+		Config:    cfg,
+		Path:      files[len(files)-1],
+		Files:     files,
+		Origins:   origins,
+		Outdated:  outdated,
+		Context:   c,
+		overrides: overrides,
+		positions: positions,
+		problems:  problems,
+	}, nil
+}
+
+// parseLayer parses a config file into a generic map, expanding ${VAR}
+// references first. The file is also decoded into a Config so type errors
+// are reported against the file rather than the merged result. Value
+// positions are recorded in positions and unknown keys appended to problems.
+// Files older than CurrentVersion are migrated first, and migrated is true.
+func parseLayer(path string, data []byte, positions map[string]Position, problems *[]Problem) (layer map[string]any, migrated bool, err error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("%s: parsing config: %w", path, err)
+	}
+
+	layer = make(map[string]any)
+	if len(doc.Content) == 0 {
+		return layer, false, nil
+	}
+	if m := doc.Content[0]; m.Kind == yaml.MappingNode {
+		applied, err := migrateNode(m)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", path, err)
+		}
+		migrated = len(applied) > 0
+	}
+	if err := interpolateNode(&doc); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := doc.Decode(Default()); err != nil {
+		return nil, false, fmt.Errorf("%s: parsing config: %w", path, err)
+	}
+	if err := doc.Decode(&layer); err != nil {
+		return nil, false, fmt.Errorf("%s: parsing config: %w", path, err)
+	}
+	walkLayer(path, &doc, reflect.TypeOf(Config{}), "", positions, problems)
+	return layer, migrated, nil
+}
+
+type keyValue struct {
+	key   string
+	value any
+}
+
+// flatten returns the leaves of a nested map as dotted keys. Lists are
+// leaves; maps are descended so layers can add individual map entries.
+func flatten(m map[string]any, prefix string) []keyValue {
+	var out []keyValue
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if sub, ok := m[k].(map[string]any); ok {
+			out = append(out, flatten(sub, key)...)
+			continue
+		}
+		out = append(out, keyValue{key, m[k]})
+	}
+	return out
+}
+
+// setPath sets a dotted key in a nested map, creating parents as needed.
+// Entry keys of map-typed keys may contain dots; see splitKey.
+func setPath(m map[string]any, key string, value any) {
+	parts, err := splitKey(key)
+	if err != nil {
+		// Unknown keys are reported by validation; keep them nested.
+		parts = strings.Split(key, ".")
+	}
+	for _, p := range parts[:len(parts)-1] {
+		sub, ok := m[p].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			m[p] = sub
+		}
+		m = sub
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// Keys returns every config key with its Go type, keyed by dotted path.
+// Structs are descended; all other types, including lists and maps, are
+// leaves.
+func Keys() map[string]reflect.Type {
+	keys := make(map[string]reflect.Type)
+	collectKeys(reflect.TypeOf(Config{}), "", keys)
+	return keys
+}
+
+func collectKeys(t reflect.Type, prefix string, keys map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if f.Type.Kind() == reflect.Struct {
+			collectKeys(f.Type, key, keys)
+			continue
+		}
+		keys[key] = f.Type
+	}
+}
+
+// knownKey reports whether key is a config key or an entry of a map-typed key
+// (e.g. "runners.extra_env.FOO").
+func knownKey(key string) bool {
+	keys := Keys()
+	if _, ok := keys[key]; ok {
+		return true
+	}
+	for k, t := range keys {
+		if t.Kind() == reflect.Map && strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is a single resolved config value and the layer that set it.
+type Entry struct {
+	Key    string
+	Value  any
+	Source string
+}
+
+// Entries returns every config value with its origin, sorted by key. Map
+// values set by a layer are listed per map entry.
+func (l *Loaded) Entries() []Entry {
+	var entries []Entry
+	for key, t := range Keys() {
+		if t.Kind() == reflect.Map {
+			v, _ := Lookup(l.Config, key)
+			m := reflect.ValueOf(v)
+			if m.Len() > 0 {
+				iter := m.MapRange()
+				for iter.Next() {
+					k := key + "." + fmt.Sprint(iter.Key().Interface())
+					entries = append(entries, Entry{k, iter.Value().Interface(), l.Origin(k)})
+				}
+				continue
+			}
+		}
+		v, _ := Lookup(l.Config, key)
+		entries = append(entries, Entry{key, v, l.Origin(key)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// Lookup returns the value of a dotted config key, including entries of
+// map-typed keys such as "runners.extra_env.FOO".
+func Lookup(cfg *Config, key string) (any, bool) {
+	v := reflect.ValueOf(cfg).Elem()
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := fieldByTag(v, p)
+			if !ok {
+				return nil, false
+			}
+			v = f
+		case reflect.Map:
+			mv := v.MapIndex(reflect.ValueOf(strings.Join(parts[i:], ".")))
+			if !mv.IsValid() {
+				return nil, false
+			}
+			return mv.Interface(), true
+		default:
+			return nil, false
+		}
+	}
+	return v.Interface(), true
+}
+
+// fieldByTag returns the struct field whose yaml tag name is name.
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	f, ok := structField(v.Type(), name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return v.FieldByIndex(f.Index), true
+}
+
+// RedactToken hides a literal token. References such as "env:GH_TOKEN" are
+// returned unchanged since they contain no secret.
+func RedactToken(raw string) string {
+	if raw == "" || strings.HasPrefix(raw, "env:") {
+		return raw
+	}
+	return "********"
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupLayers creates a system, user and project config and points the
+// search paths at them.
+func setupLayers(t *testing.T) (system, user, project string) {
+	t.Helper()
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	repo := filepath.Join(root, "repo")
+	sub := filepath.Join(repo, "a", "b")
+
+	system = filepath.Join(root, "etc", "config.yaml")
+	user = filepath.Join(home, ".ghr", "config.yaml")
+	project = filepath.Join(repo, ProjectConfigName)
+
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(system, "org: sys-org\nrunners:\n  image: sys/image\n  count: 3\n")
+	write(user, "org: user-org\nrunners:\n  extra_env:\n    A: user\n    B: user\n")
+	write(project, "runners:\n  count: 12\n  labels: [gpu]\n  extra_env:\n    B: project\n")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	old := systemConfigPath
+	systemConfigPath = system
+	t.Cleanup(func() { systemConfigPath = old })
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Chdir(sub)
+	return system, user, project
+}
+
+func TestLoadLayers(t *testing.T) {
+	system, user, project := setupLayers(t)
+
+	loaded, err := LoadLayers("", nil)
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	cfg := loaded.Config
+
+	if len(loaded.Files) != 3 || loaded.Files[0] != system || loaded.Files[2] != project {
+		t.Errorf("Files = %v", loaded.Files)
+	}
+	if loaded.Path != project {
+		t.Errorf("Path = %q, want %q", loaded.Path, project)
+	}
+
+	checks := []struct {
+		key    string
+		got    any
+		want   any
+		source string
+	}{
+		{"org", cfg.Org, "user-org", user},
+		{"runners.image", cfg.Runners.Image, "sys/image", system},
+		{"runners.count", cfg.Runners.Count, 12, project},
+		{"runners.extra_env.A", cfg.Runners.ExtraEnv["A"], "user", user},
+		{"runners.extra_env.B", cfg.Runners.ExtraEnv["B"], "project", project},
+		{"runners.name_prefix", cfg.Runners.NamePrefix, "ghr", SourceDefault},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.key, c.got, c.want)
+		}
+		if src := loaded.Origin(c.key); src != c.source {
+			t.Errorf("Origin(%s) = %q, want %q", c.key, src, c.source)
+		}
+	}
+	if len(cfg.Runners.Labels) != 1 || cfg.Runners.Labels[0] != "gpu" {
+		t.Errorf("labels = %v, want [gpu]", cfg.Runners.Labels)
+	}
+}
+
+func TestLoadLayersEnvAndFlags(t *testing.T) {
+	setupLayers(t)
+	t.Setenv("GHR_RUNNERS_COUNT", "20")
+	t.Setenv("GHR_ORG", "env-org")
+	t.Setenv("GHR_DOCKER_RESTART_POLICY", "always")
+
+	loaded, err := LoadLayers("", []string{"runners.count=30", "runners.extra_env.C=flag"})
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	cfg := loaded.Config
+
+	if cfg.Org != "env-org" || loaded.Origin("org") != "env GHR_ORG" {
+		t.Errorf("org = %q from %q", cfg.Org, loaded.Origin("org"))
+	}
+	if cfg.Docker.RestartPolicy != "always" {
+		t.Errorf("restart_policy = %q, want always", cfg.Docker.RestartPolicy)
+	}
+	if cfg.Runners.Count != 30 || loaded.Origin("runners.count") != "flag --set" {
+		t.Errorf("count = %d from %q, want 30 from flag", cfg.Runners.Count, loaded.Origin("runners.count"))
+	}
+	if cfg.Runners.ExtraEnv["C"] != "flag" || cfg.Runners.ExtraEnv["A"] != "user" {
+		t.Errorf("extra_env = %v", cfg.Runners.ExtraEnv)
+	}
+
+	if _, err := LoadLayers("", []string{"runners.nope=1"}); err == nil {
+		t.Error("LoadLayers() expected error for unknown --set key")
+	}
+	t.Setenv("GHR_RUNNERS_COUNT", "many")
+	if _, err := LoadLayers("", nil); err == nil {
+		t.Error("LoadLayers() expected error for invalid integer env var")
+	}
+}
+
+func TestLoadLayersExplicitPath(t *testing.T) {
+	setupLayers(t)
+	path := filepath.Join(t.TempDir(), "only.yaml")
+	if err := os.WriteFile(path, []byte("org: explicit\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLayers(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	if len(loaded.Files) != 1 || loaded.Config.Org != "explicit" || loaded.Config.Runners.Image != Default().Runners.Image {
+		t.Errorf("explicit path should replace discovered files: %+v", loaded)
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config format version this ghr reads and writes.
+// Files without a version field are version 0, the format used before the
+// field was introduced.
+const CurrentVersion = 1
+
+// Migration upgrades a config file from Version to Version+1. Apply edits
+// the file's top-level mapping node in place so comments are kept; the
+// version field itself is updated by the caller.
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(m *yaml.Node) error
+}
+
+// migrations holds one entry per format version, indexed by the version
+// they upgrade from. When the format changes, bump CurrentVersion and append
+// the migration from the previous version.
+var migrations = []Migration{
+	{
+		Version:     0,
+		Description: "add the version field",
+		Apply:       func(m *yaml.Node) error { return nil },
+	},
+}
+
+// FileVersion returns the format version of a top-level config mapping.
+func FileVersion(m *yaml.Node) (int, error) {
+	v := mappingValue(m, "version")
+	if v == nil {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v.Value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("line %d: invalid version %q", v.Line, v.Value)
+	}
+	if n > CurrentVersion {
+		return 0, fmt.Errorf("line %d: config version %d is newer than this ghr supports (%d); upgrade ghr", v.Line, n, CurrentVersion)
+	}
+	return n, nil
+}
+
+// migrateNode upgrades a top-level config mapping to CurrentVersion and
+// returns the migrations it applied.
+func migrateNode(m *yaml.Node) ([]Migration, error) {
+	from, err := FileVersion(m)
+	if err != nil {
+		return nil, err
+	}
+	var applied []Migration
+	for _, mig := range migrations[from:] {
+		if err := mig.Apply(m); err != nil {
+			return applied, fmt.Errorf("migrating from version %d: %w", mig.Version, err)
+		}
+		applied = append(applied, mig)
+	}
+	if len(applied) > 0 {
+		setVersion(m, CurrentVersion)
+	}
+	return applied, nil
+}
+
+// setVersion sets the version field, adding it as the first key if missing.
+// A comment heading the file stays above the new key.
+func setVersion(m *yaml.Node, version int) {
+	value := strconv.Itoa(version)
+	if v := mappingValue(m, "version"); v != nil {
+		v.Value, v.Tag, v.Style = value, "!!int", 0
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	if len(m.Content) > 0 {
+		key.HeadComment, m.Content[0].HeadComment = m.Content[0].HeadComment, ""
+	}
+	m.Content = append([]*yaml.Node{key, {Kind: yaml.ScalarNode, Tag: "!!int", Value: value}}, m.Content...)
+}
+
+// Version returns the document's config format version.
+func (d *Document) Version() (int, error) {
+	return FileVersion(d.root.Content[0])
+}
+
+// Migrate upgrades the document to CurrentVersion and returns the
+// migrations applied, which is empty if it was already current.
+func (d *Document) Migrate() ([]Migration, error) {
+	applied, err := migrateNode(d.root.Content[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", d.Path, err)
+	}
+	return applied, nil
+}
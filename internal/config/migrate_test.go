@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDocumentMigrate(t *testing.T) {
+	src := "# fleet config\nscope: org\norg: acme # the org\n"
+	doc, err := ParseDocument("config.yaml", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := doc.Version(); err != nil || v != 0 {
+		t.Fatalf("Version() = %d, %v; want 0", v, err)
+	}
+
+	applied, err := doc.Migrate()
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(applied) != CurrentVersion {
+		t.Errorf("applied %d migrations, want %d", len(applied), CurrentVersion)
+	}
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# fleet config\nversion: 1\nscope: org\norg: acme # the org\n"
+	if string(data) != want {
+		t.Errorf("migrated document =\n%s\nwant\n%s", data, want)
+	}
+
+	applied, err = doc.Migrate()
+	if err != nil || len(applied) != 0 {
+		t.Errorf("second Migrate() = %d migrations, %v; want none", len(applied), err)
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	doc, err := ParseDocument("config.yaml", []byte("version: 99\nscope: org\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.Migrate(); err == nil || !strings.Contains(err.Error(), "newer than this ghr supports") {
+		t.Errorf("Migrate() error = %v, want newer-version error", err)
+	}
+}
+
+func TestLoadLayersOutdated(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.yaml")
+	current := filepath.Join(dir, "current.yaml")
+	if err := os.WriteFile(old, []byte("org: acme\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(current, []byte("version: 1\norg: acme\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLayers(old, nil)
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	if len(loaded.Outdated) != 1 || loaded.Outdated[0] != old {
+		t.Errorf("Outdated = %v, want [%s]", loaded.Outdated, old)
+	}
+	if loaded.Config.Version != CurrentVersion || loaded.Config.Org != "acme" {
+		t.Errorf("Config = %+v", loaded.Config)
+	}
+
+	loaded, err = LoadLayers(current, nil)
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	if len(loaded.Outdated) != 0 {
+		t.Errorf("Outdated = %v, want none", loaded.Outdated)
+	}
+}
+
+func TestSchemaDescribesEveryKey(t *testing.T) {
+	for key := range Keys() {
+		if _, ok := descriptions[key]; !ok {
+			t.Errorf("no schema description for %q", key)
+		}
+	}
+	props := Schema()["properties"].(map[string]any)
+	runners := props["runners"].(map[string]any)["properties"].(map[string]any)
+	pattern, ok := runners["name_prefix"].(map[string]any)["pattern"].(string)
+	if !ok {
+		t.Fatal("runners.name_prefix has no pattern")
+	}
+	re := regexp.MustCompile(pattern)
+	for prefix, want := range map[string]bool{
+		"ghr":            true,
+		"ci-{{.Host}}":   true,
+		"{{.Pool}}-ci":   true,
+		"-ghr":           false,
+		"my runners":     false,
+		"ghr/{{.Host}}x": true, // templates are checked when loading
+	} {
+		if got := re.MatchString(prefix); got != want {
+			t.Errorf("name_prefix pattern matches %q = %v, want %v", prefix, got, want)
+		}
+	}
+}
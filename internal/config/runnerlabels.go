@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RunnerLabels records the custom labels given to live runners with
+// `ghr label`, keyed by container ID. Docker cannot change the labels of an
+// existing container, so the record is kept next to the config instead. A
+// runner's entry no longer applies once its container is replaced, and is
+// dropped by Prune once the container is gone.
+type RunnerLabels struct {
+	Runners map[string]RunnerLabelsEntry `yaml:"runners"`
+}
+
+// RunnerLabelsEntry is the record for one runner container.
+type RunnerLabelsEntry struct {
+	Name string `yaml:"name"`
+	// Scope and Target are the scope and the org, "owner/name" or
+	// enterprise slug the runner is registered with. Configs only prune the
+	// records of their own targets, so several configs can share the file.
+	Scope  string `yaml:"scope"`
+	Target string `yaml:"target"`
+	// Labels are the runner's custom labels on GitHub after the last change,
+	// including the ones it registered with.
+	Labels []string `yaml:"labels"`
+}
+
+// RunnerLabelsPath returns the path of the runner labels file
+// (~/.ghr/runner-labels.yaml).
+func RunnerLabelsPath() string {
+	return filepath.Join(Dir(), "runner-labels.yaml")
+}
+
+// LoadRunnerLabels reads the runner labels file. A missing file yields no
+// records.
+func LoadRunnerLabels() (*RunnerLabels, error) {
+	rl := &RunnerLabels{Runners: make(map[string]RunnerLabelsEntry)}
+	data, err := os.ReadFile(RunnerLabelsPath())
+	if os.IsNotExist(err) {
+		return rl, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading runner labels: %w", err)
+	}
+	if err := yaml.Unmarshal(data, rl); err != nil {
+		return nil, fmt.Errorf("%s: parsing runner labels: %w", RunnerLabelsPath(), err)
+	}
+	if rl.Runners == nil {
+		rl.Runners = make(map[string]RunnerLabelsEntry)
+	}
+	return rl, nil
+}
+
+// Save writes the runner labels file.
+func (rl *RunnerLabels) Save() error {
+	data, err := yaml.Marshal(rl)
+	if err != nil {
+		return fmt.Errorf("marshaling runner labels: %w", err)
+	}
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+	return os.WriteFile(RunnerLabelsPath(), data, 0o644)
+}
+
+// Record stores the labels of the runner in container id, dropping the
+// record of an earlier container with the same runner name and target.
+func (rl *RunnerLabels) Record(id string, e RunnerLabelsEntry) {
+	for other, old := range rl.Runners {
+		if old.Name == e.Name && old.Scope == e.Scope && old.Target == e.Target && other != id {
+			delete(rl.Runners, other)
+		}
+	}
+	rl.Runners[id] = e
+}
+
+// Prune drops the records of cfg's runners whose container is not in live,
+// a set of container IDs, and reports whether any were dropped. Records of
+// other configs' targets are kept.
+func (rl *RunnerLabels) Prune(cfg *Config, live map[string]bool) bool {
+	pruned := false
+	for id, e := range rl.Runners {
+		if !live[id] && cfg.servesTarget(e.Scope, e.Target) {
+			delete(rl.Runners, id)
+			pruned = true
+		}
+	}
+	return pruned
+}
+
+// servesTarget reports whether runners registered with the given scope and
+// target belong to c.
+func (c *Config) servesTarget(scope, target string) bool {
+	if scope != c.Scope {
+		return false
+	}
+	switch scope {
+	case "org":
+		return target == c.Org
+	case "enterprise":
+		return target == c.Enterprise
+	}
+	for _, p := range c.Pools() {
+		if target == p.Repo.FullName() {
+			return true
+		}
+	}
+	return false
+}
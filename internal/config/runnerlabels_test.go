@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRunnerLabelsSaveLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	rl, err := LoadRunnerLabels()
+	if err != nil {
+		t.Fatalf("LoadRunnerLabels() error = %v", err)
+	}
+	if len(rl.Runners) != 0 {
+		t.Errorf("LoadRunnerLabels() = %v, want no records without a file", rl.Runners)
+	}
+
+	entry := func(name string, labels ...string) RunnerLabelsEntry {
+		return RunnerLabelsEntry{Name: name, Scope: "org", Target: "myorg", Labels: labels}
+	}
+	rl.Record("aaaa", entry("ghr-runner-1", "linux", "team-ml"))
+	rl.Record("bbbb", entry("ghr-runner-2", "linux"))
+	// A new container for runner 1 replaces the old record.
+	rl.Record("cccc", entry("ghr-runner-1", "linux"))
+	if err := rl.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	rl, err = LoadRunnerLabels()
+	if err != nil {
+		t.Fatalf("LoadRunnerLabels() error = %v", err)
+	}
+	want := map[string]RunnerLabelsEntry{
+		"bbbb": entry("ghr-runner-2", "linux"),
+		"cccc": entry("ghr-runner-1", "linux"),
+	}
+	if !reflect.DeepEqual(rl.Runners, want) {
+		t.Errorf("LoadRunnerLabels() = %v, want %v", rl.Runners, want)
+	}
+}
+
+func TestRunnerLabelsPrune(t *testing.T) {
+	rl := &RunnerLabels{Runners: map[string]RunnerLabelsEntry{
+		"aaaa": {Name: "ghr-runner-1", Scope: "org", Target: "myorg"},
+		"bbbb": {Name: "ghr-runner-2", Scope: "org", Target: "myorg"},
+		"cccc": {Name: "ghr-runner-1", Scope: "org", Target: "otherorg"},
+		"dddd": {Name: "ghr-app-runner-1", Scope: "repo", Target: "me/app"},
+	}}
+	cfg := Default()
+	cfg.Org = "myorg"
+
+	if !rl.Prune(cfg, map[string]bool{"aaaa": true}) {
+		t.Error("Prune() = false, want true")
+	}
+	var ids []string
+	for id := range rl.Runners {
+		ids = append(ids, id)
+	}
+	// Only myorg's record of a gone container is dropped.
+	if len(rl.Runners) != 3 || rl.Runners["bbbb"].Name != "" {
+		t.Errorf("records after Prune() = %v, want aaaa, cccc and dddd", ids)
+	}
+
+	repos := Default()
+	repos.Scope, repos.Org = "repo", ""
+	repos.Repos = []RepoPoolConf{{Owner: "me", Name: "app"}}
+	if !rl.Prune(repos, nil) || len(rl.Runners) != 2 {
+		t.Errorf("Prune(repos) left %v, want the me/app record dropped", rl.Runners)
+	}
+	if rl.Prune(repos, nil) {
+		t.Error("second Prune() = true, want false")
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// descriptions documents every config key for the JSON Schema. Keys use the
+// same dotted form as Keys(), with "[]" marking list items.
+var descriptions = map[string]string{
+	"version":                    "Config format version. Older files are upgraded with `ghr config migrate`.",
+	"scope":                      "Register runners for an organization (org), a single repository (repo) or an enterprise (enterprise).",
+	"org":                        "Organization name, required when scope is org.",
+	"repo":                       "Repository, required when scope is repo.",
+	"repo.owner":                 "Repository owner.",
+	"repo.name":                  "Repository name.",
+	"repos":                      "Several repositories for repo scope, each with its own runners; replaces repo.",
+	"repos[].owner":              "Repository owner.",
+	"repos[].name":               "Repository name.",
+	"repos[].count":              "Number of runners for the repository; runners.count when 0.",
+	"repos[].name_prefix":        "Name prefix for the repository's runners; defaults to <runners.name_prefix>-<name>.",
+	"repos[].labels":             "Labels added to runners.labels for the repository's runners.",
+	"enterprise":                 "Enterprise slug, required when scope is enterprise.",
+	"token":                      "GitHub token, or env:VAR to read it from an environment variable.",
+	"github":                     "GitHub Enterprise Server settings; github.com is used when empty.",
+	"github.base_url":            "GitHub Enterprise Server URL, e.g. https://ghe.example.com; the API is reached at <base_url>/api/v3/.",
+	"github.upload_url":          "Upload URL, if different from github.base_url.",
+	"runners":                    "Runner container settings.",
+	"runners.count":              "Default number of runners for ghr up.",
+	"runners.image":              "Runner container image.",
+	"runners.labels":             "Labels assigned to every runner; may use templates such as {{.Num}}, and labels that render empty are left out.",
+	"runners.group":              "Runner group to register runners in.",
+	"runners.name_prefix":        "Prefix for container and runner names (<prefix>-runner-<n>); may use templates such as {{.Host}}.",
+	"runners.ephemeral":          "Register runners as ephemeral, running a single job each.",
+	"runners.extra_env":          "Extra environment variables passed to runner containers; values may use templates such as {{.Num}}.",
+	"docker":                     "Docker settings.",
+	"docker.host":                "Docker endpoint, e.g. unix:///run/user/1000/docker.sock, tcp://host:2376 or ssh://user@host; overrides DOCKER_HOST and the Docker CLI context.",
+	"docker.context":             "Docker CLI context to use instead of the current one; ignored when docker.host is set.",
+	"docker.tls":                 "TLS client settings for a tcp:// docker.host.",
+	"docker.tls.ca":              "CA certificate file used to verify the Docker host; the system roots are used when empty.",
+	"docker.tls.cert":            "Client certificate file.",
+	"docker.tls.key":             "Client private key file.",
+	"docker.tls.skip_verify":     "Do not verify the Docker host's certificate.",
+	"docker.socket":              "Docker socket path.",
+	"docker.mount_docker_socket": "Mount the Docker socket into runner containers.",
+	"docker.restart_policy":      "Docker restart policy for runner containers.",
+	"docker.work_dir_base":       "Host directory for runner work directories; a named volume is used when empty.",
+	"hosts":                      "Docker hosts to spread runners over (fleet mode); the docker: endpoint is used when empty.",
+	"hosts[].name":               "Host name shown in the HOST column.",
+	"hosts[].host":               "Docker endpoint of the host; see docker.host.",
+	"hosts[].context":            "Docker CLI context of the host; see docker.context.",
+	"hosts[].tls":                "TLS client settings for a tcp:// host; see docker.tls.",
+	"hosts[].tls.ca":             "CA certificate file used to verify the host.",
+	"hosts[].tls.cert":           "Client certificate file.",
+	"hosts[].tls.key":            "Client private key file.",
+	"hosts[].tls.skip_verify":    "Do not verify the host's certificate.",
+	"hosts[].socket":             "Docker socket path on the host, if different from docker.socket.",
+	"hosts[].weight":             "Share of runners placed on the host relative to the other hosts; defaults to 1.",
+	"hosts[].max_runners":        "Most runners placed on the host; 0 means no limit.",
+	"schedules":                  "Cron schedules for ghr schedule run.",
+	"schedules[].name":           "Schedule name shown in output.",
+	"schedules[].cron":           "Five-field cron expression at which the count takes effect.",
+	"schedules[].count":          "Runner count from this schedule until the next one fires.",
+	"schedules[].timezone":       "IANA time zone the cron expression is evaluated in.",
+}
+
+// Schema returns a JSON Schema (draft-07) describing the config file, for
+// editor completion and validation.
+func Schema() map[string]any {
+	s := typeSchema(reflect.TypeOf(Config{}), "")
+	s["$schema"] = "http://json-schema.org/draft-07/schema#"
+	s["title"] = "ghr config"
+	return s
+}
+
+func typeSchema(t reflect.Type, key string) map[string]any {
+	s := make(map[string]any)
+	switch t.Kind() {
+	case reflect.Struct:
+		props := make(map[string]any)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			props[name] = typeSchema(f.Type, joinKey(key, name))
+		}
+		s["type"] = "object"
+		s["properties"] = props
+		s["additionalProperties"] = false
+	case reflect.Slice:
+		s["type"] = "array"
+		s["items"] = typeSchema(t.Elem(), key+"[]")
+	case reflect.Map:
+		s["type"] = "object"
+		s["additionalProperties"] = typeSchema(t.Elem(), "")
+	case reflect.String:
+		s["type"] = "string"
+	case reflect.Int:
+		s = interpolated("integer")
+	case reflect.Bool:
+		s = interpolated("boolean")
+	}
+
+	switch key {
+	case "version":
+		s = map[string]any{"type": "integer", "minimum": 0, "maximum": CurrentVersion}
+	case "scope":
+		s["enum"] = []string{"org", "repo", "enterprise"}
+	case "docker.restart_policy":
+		s["enum"] = restartPolicies
+	case "runners.name_prefix":
+		// Templates are checked when the config is loaded.
+		plain := strings.TrimSuffix(strings.TrimPrefix(namePrefixRe.String(), "^"), "$")
+		s["pattern"] = `^(?:` + plain + `|.*\{\{.*\}\}.*)$`
+	case "runners.labels[]":
+		s["minLength"] = 1
+		s["maxLength"] = maxLabelLength
+		s["pattern"] = `^[^,]*$`
+	case "runners.extra_env":
+		s["propertyNames"] = map[string]any{"not": map[string]any{"enum": reservedEnv}}
+	case "hosts[]":
+		s["required"] = []string{"name"}
+	case "schedules[]":
+		s["required"] = []string{"cron", "timezone"}
+	}
+	if d, ok := descriptions[key]; ok {
+		s["description"] = d
+	}
+	return s
+}
+
+// interpolated allows a non-string value to also be written as a ${VAR}
+// reference, which is resolved before the file is decoded.
+func interpolated(typ string) map[string]any {
+	return map[string]any{
+		"anyOf": []any{
+			map[string]any{"type": typ},
+			map[string]any{"type": "string", "pattern": `^.*\$\{[A-Za-z_][A-Za-z0-9_]*(:-[^}]*)?\}.*$`},
+		},
+	}
+}
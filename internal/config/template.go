@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// RunnerVars are the per-runner values available to templates in
+// runners.name_prefix, runners.labels and runners.extra_env values, e.g.
+// "cache-{{.Num}}".
+type RunnerVars struct {
+	Num   int    // runner number
+	Host  string // fleet host name; empty unless hosts are configured
+	Pool  string // repo name of the runner's repos: entry; empty without repos
+	Index int    // 0-based position of the runner among its pool's runners on its host
+}
+
+// templateFuncs are the functions available to runner templates, besides
+// Go's built-ins such as eq and printf.
+var templateFuncs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+	"sub": func(a, b int) int { return a - b },
+	"mod": func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("mod by zero")
+		}
+		return a % b, nil
+	},
+}
+
+// IsTemplate reports whether s contains template actions.
+func IsTemplate(s string) bool {
+	return strings.Contains(s, "{{")
+}
+
+// render executes s as a runner template. Strings without actions are
+// returned as they are.
+func render(s string, v RunnerVars) (string, error) {
+	if !IsTemplate(s) {
+		return s, nil
+	}
+	t, err := template.New("").Funcs(templateFuncs).Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, v); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Render returns a copy of c with the templates in runners.name_prefix,
+// runners.labels and runners.extra_env values executed for one runner.
+// Labels that render empty are left out, so a label can be made conditional.
+func (c *Config) Render(v RunnerVars) (*Config, error) {
+	r := *c
+	var err error
+	if r.Runners.NamePrefix, err = render(c.Runners.NamePrefix, v); err != nil {
+		return nil, fmt.Errorf("runners.name_prefix: %w", err)
+	}
+	r.Runners.Labels = nil
+	for i, l := range c.Runners.Labels {
+		l, err := render(l, v)
+		if err != nil {
+			return nil, fmt.Errorf("runners.labels[%d]: %w", i, err)
+		}
+		if l = strings.TrimSpace(l); l != "" {
+			r.Runners.Labels = append(r.Runners.Labels, l)
+		}
+	}
+	if c.Runners.ExtraEnv != nil {
+		r.Runners.ExtraEnv = make(map[string]string, len(c.Runners.ExtraEnv))
+		for k, val := range c.Runners.ExtraEnv {
+			if r.Runners.ExtraEnv[k], err = render(val, v); err != nil {
+				return nil, fmt.Errorf("runners.extra_env.%s: %w", k, err)
+			}
+		}
+	}
+	return &r, nil
+}
+
+// NamePattern returns a pattern matching the names of runners with the
+// given name prefix, which may be a template.
+func NamePattern(prefix string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for prefix != "" {
+		i := strings.Index(prefix, "{{")
+		if i < 0 {
+			b.WriteString(regexp.QuoteMeta(prefix))
+			break
+		}
+		b.WriteString(regexp.QuoteMeta(prefix[:i]))
+		j := strings.Index(prefix[i:], "}}")
+		if j < 0 {
+			break
+		}
+		b.WriteString(".*")
+		prefix = prefix[i+j+2:]
+	}
+	b.WriteString(`-runner-\d+$`)
+	return regexp.MustCompile(b.String())
+}
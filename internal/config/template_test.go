@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	c := Default()
+	c.Runners.NamePrefix = "ci-{{.Pool}}"
+	c.Runners.Labels = []string{"linux", `{{if eq (mod .Num 2) 1}}arm-emulated{{end}}`, "slot-{{.Index}}"}
+	c.Runners.ExtraEnv = map[string]string{"CACHE_DIR": "/cache/{{.Host}}/{{.Num}}", "PLAIN": "x"}
+
+	tests := []struct {
+		vars   RunnerVars
+		prefix string
+		labels []string
+		cache  string
+	}{
+		{RunnerVars{Num: 3, Host: "a", Pool: "app", Index: 1}, "ci-app", []string{"linux", "arm-emulated", "slot-1"}, "/cache/a/3"},
+		{RunnerVars{Num: 4, Host: "b", Pool: "app"}, "ci-app", []string{"linux", "slot-0"}, "/cache/b/4"},
+	}
+	for _, tt := range tests {
+		r, err := c.Render(tt.vars)
+		if err != nil {
+			t.Fatalf("Render(%+v) error = %v", tt.vars, err)
+		}
+		if r.Runners.NamePrefix != tt.prefix || !reflect.DeepEqual(r.Runners.Labels, tt.labels) ||
+			r.Runners.ExtraEnv["CACHE_DIR"] != tt.cache || r.Runners.ExtraEnv["PLAIN"] != "x" {
+			t.Errorf("Render(%+v) = %q, %v, %v", tt.vars, r.Runners.NamePrefix, r.Runners.Labels, r.Runners.ExtraEnv)
+		}
+	}
+	if c.Runners.NamePrefix != "ci-{{.Pool}}" || len(c.Runners.Labels) != 3 || c.Runners.ExtraEnv["CACHE_DIR"] != "/cache/{{.Host}}/{{.Num}}" {
+		t.Errorf("Render() changed the original config: %+v", c.Runners)
+	}
+
+	c.Runners.Labels = []string{"{{mod .Num 0}}"}
+	if _, err := c.Render(RunnerVars{Num: 1}); err == nil {
+		t.Error("Render() with mod by zero succeeded, want error")
+	}
+}
+
+func TestNamePattern(t *testing.T) {
+	tests := []struct {
+		prefix, name string
+		want         bool
+	}{
+		{"ghr", "ghr-runner-3", true},
+		{"ghr", "ghr-runner-x", false},
+		{"ghr", "xghr-runner-3", false},
+		{"g.r", "ghr-runner-3", false},
+		{"ci-{{.Host}}", "ci-build-1-runner-3", true},
+		{"ci-{{.Host}}", "cd-build-1-runner-3", false},
+		{"{{.Pool}}-{{.Num}}", "app-3-runner-3", true},
+	}
+	for _, tt := range tests {
+		if got := NamePattern(tt.prefix).MatchString(tt.name); got != tt.want {
+			t.Errorf("NamePattern(%q).MatchString(%q) = %v, want %v", tt.prefix, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,556 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a location in a config file.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// Problem is a single validation failure. Key is the dotted config key it
+// concerns, with list indexes in brackets (e.g. "schedules[1].cron"). Source
+// and Pos are filled in when the problem can be traced to a layer.
+type Problem struct {
+	Key     string
+	Message string
+	Source  string
+	Pos     Position
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.Pos.File != "":
+		return fmt.Sprintf("%s:%d:%d: %s: %s", p.Pos.File, p.Pos.Line, p.Pos.Column, p.Key, p.Message)
+	case p.Source != "":
+		return fmt.Sprintf("%s: %s (from %s)", p.Key, p.Message, p.Source)
+	default:
+		return fmt.Sprintf("%s: %s", p.Key, p.Message)
+	}
+}
+
+// ValidationError reports every problem found in a config.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problems:", len(e.Problems))
+	for _, p := range e.Problems {
+		b.WriteString("\n  " + p.String())
+	}
+	return b.String()
+}
+
+// reservedEnv lists the container environment variables ghr sets for every
+// runner; runners.extra_env must not override them.
+var reservedEnv = []string{
+	"RUNNER_SCOPE", "RUNNER_NAME", "RUNNER_LABELS", "RUNNER_GROUP",
+	"ACCESS_TOKEN", "ORG_NAME", "REPO_URL", "EPHEMERAL",
+	"ENTERPRISE_NAME", "GITHUB_HOST",
+}
+
+// restartPolicies are the Docker restart policies accepted by docker.restart_policy.
+var restartPolicies = []string{"no", "always", "unless-stopped", "on-failure"}
+
+// dockerHostSchemes are the URL schemes accepted by docker.host.
+var dockerHostSchemes = []string{"unix", "tcp", "ssh", "npipe"}
+
+// namePrefixRe matches prefixes that yield valid Docker container names
+// ("<prefix>-runner-<n>").
+var namePrefixRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// templateErrRe matches the position prefix of text/template errors, which
+// means nothing for a template taken from a single config value.
+var templateErrRe = regexp.MustCompile(`^template: :\d+(:\d+)?: (executing "" at )?`)
+
+// maxLabelLength is the longest runner label GitHub accepts.
+const maxLabelLength = 256
+
+// Validate checks the config and returns a *ValidationError listing every
+// problem found, or nil if the config is valid.
+func Validate(cfg *Config) error {
+	if problems := validate(cfg); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ValidateKey validates cfg and returns the first problem with key or any
+// key below it (such as "runners.labels[1]" for "runners.labels"), or nil.
+func ValidateKey(cfg *Config, key string) error {
+	for _, p := range validate(cfg) {
+		if p.Key == key || strings.HasPrefix(p.Key, key+".") || strings.HasPrefix(p.Key, key+"[") {
+			return errors.New(p.Message)
+		}
+	}
+	return nil
+}
+
+// Validate checks the merged config like the package-level Validate, and
+// additionally reports unknown keys. Problems are annotated with the file,
+// line and column or the environment variable or flag that set the value.
+func (l *Loaded) Validate() error {
+	problems := append(append([]Problem{}, l.problems...), validate(l.Config)...)
+	if len(problems) == 0 {
+		return nil
+	}
+	for i := range problems {
+		l.locate(&problems[i])
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i].Pos, problems[j].Pos
+		if (a.File == "") != (b.File == "") {
+			return a.File != ""
+		}
+		if a.File != b.File {
+			return l.fileIndex(a.File) < l.fileIndex(b.File)
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return &ValidationError{Problems: problems}
+}
+
+// locate fills in the layer that set the problem's key and, when that layer
+// is a file, the key's position in it.
+func (l *Loaded) locate(p *Problem) {
+	if p.Pos.File != "" {
+		return
+	}
+	for key := p.Key; key != ""; key = parentKey(key) {
+		origin, ok := l.Origins[key]
+		if !ok {
+			continue
+		}
+		p.Source = origin
+		if pos, ok := l.positions[p.Key]; ok && pos.File == origin {
+			p.Pos = pos
+		}
+		return
+	}
+}
+
+func (l *Loaded) fileIndex(path string) int {
+	for i, f := range l.Files {
+		if f == path {
+			return i
+		}
+	}
+	return len(l.Files)
+}
+
+// parentKey strips the last ".name" or "[i]" element from key.
+func parentKey(key string) string {
+	i := strings.LastIndexAny(key, ".[")
+	if i < 0 {
+		return ""
+	}
+	return key[:i]
+}
+
+func validate(cfg *Config) []Problem {
+	var problems []Problem
+	add := func(key, format string, args ...any) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch cfg.Scope {
+	case "org":
+		if cfg.Org == "" {
+			add("org", "is required when scope is 'org'")
+		}
+	case "repo":
+		switch {
+		case len(cfg.Repos) > 0:
+			if cfg.Repo != (RepoConfig{}) {
+				add("repo", "cannot be used together with repos")
+			}
+		default:
+			if cfg.Repo.Owner == "" {
+				add("repo.owner", "is required when scope is 'repo'")
+			}
+			if cfg.Repo.Name == "" {
+				add("repo.name", "is required when scope is 'repo'")
+			}
+		}
+	case "enterprise":
+		if cfg.Enterprise == "" {
+			add("enterprise", "is required when scope is 'enterprise'")
+		}
+	default:
+		add("scope", "must be 'org', 'repo' or 'enterprise', got %q", cfg.Scope)
+	}
+	if cfg.Token == "" {
+		add("token", "is required")
+	}
+	validateGitHubURL(add, "github.base_url", cfg.GitHub.BaseURL)
+	validateGitHubURL(add, "github.upload_url", cfg.GitHub.UploadURL)
+	if cfg.GitHub.UploadURL != "" && cfg.GitHub.BaseURL == "" {
+		add("github.upload_url", "requires github.base_url")
+	}
+
+	if cfg.Runners.Count < 0 {
+		add("runners.count", "must not be negative, got %d", cfg.Runners.Count)
+	}
+	if cfg.Runners.Image == "" {
+		add("runners.image", "is required")
+	}
+	sample := sampleVars(cfg)
+	prefix, prefixOK := renderSample(add, "runners.name_prefix", cfg.Runners.NamePrefix, sample)
+	switch {
+	case cfg.Runners.NamePrefix == "":
+		add("runners.name_prefix", "is required")
+	case prefixOK && !namePrefixRe.MatchString(prefix):
+		add("runners.name_prefix", "%q would produce invalid container names; use letters, digits, '_', '.' and '-', starting with a letter or digit", prefix)
+	}
+
+	validateLabels(add, "runners.labels", renderLabels(add, "runners.labels", cfg.Runners.Labels, sample), make(map[string]string))
+	validateRepos(add, cfg)
+
+	envKeys := make([]string, 0, len(cfg.Runners.ExtraEnv))
+	for k := range cfg.Runners.ExtraEnv {
+		envKeys = append(envKeys, k)
+	}
+	sort.Strings(envKeys)
+	for _, k := range envKeys {
+		key := "runners.extra_env." + k
+		switch {
+		case k == "" || strings.ContainsAny(k, "= \t"):
+			add(key, "invalid environment variable name %q", k)
+		case contains(reservedEnv, k):
+			add(key, "%s is set by ghr and cannot be overridden", k)
+		}
+		renderSample(add, key, cfg.Runners.ExtraEnv[k], sample)
+	}
+
+	validateEndpoint(add, "docker", cfg.Docker.Host, cfg.Docker.TLS)
+	if !contains(restartPolicies, cfg.Docker.RestartPolicy) {
+		add("docker.restart_policy", "invalid value %q; expected one of %s", cfg.Docker.RestartPolicy, strings.Join(restartPolicies, ", "))
+	}
+	// The directory lives on the Docker host, which may be remote, so only
+	// ghr doctor checks that it exists.
+	if dir := cfg.Docker.WorkDirBase; dir != "" && !filepath.IsAbs(dir) {
+		add("docker.work_dir_base", "must be an absolute path, got %q", dir)
+	}
+
+	hostNames := make(map[string]int)
+	for i, h := range cfg.Hosts {
+		key := fmt.Sprintf("hosts[%d]", i)
+		switch j, dup := hostNames[h.Name]; {
+		case h.Name == "":
+			add(key+".name", "is required")
+		case !namePrefixRe.MatchString(h.Name):
+			add(key+".name", "%q is not a valid host name; use letters, digits, '_', '.' and '-'", h.Name)
+		case dup:
+			add(key+".name", "duplicate host name %q (same as hosts[%d])", h.Name, j)
+		default:
+			hostNames[h.Name] = i
+		}
+		validateEndpoint(add, key, h.Host, h.TLS)
+		if h.Weight < 0 {
+			add(key+".weight", "must not be negative, got %d", h.Weight)
+		}
+		if h.MaxRunners < 0 {
+			add(key+".max_runners", "must not be negative, got %d", h.MaxRunners)
+		}
+	}
+
+	for i, s := range cfg.Schedules {
+		if _, err := s.Parse(); err != nil {
+			add(fmt.Sprintf("schedules[%d]", i), "%v", err)
+		}
+	}
+	return problems
+}
+
+// validateEndpoint checks the host and tls settings under prefix ("docker"
+// or "hosts[i]").
+func validateEndpoint(add func(key, format string, args ...any), prefix, host string, tls DockerTLSConf) {
+	if host != "" {
+		if scheme, _, ok := strings.Cut(host, "://"); !ok || !contains(dockerHostSchemes, scheme) {
+			add(prefix+".host", "invalid Docker host %q; expected a URL with scheme %s", host, strings.Join(dockerHostSchemes, ", "))
+		} else if tls.Enabled() && scheme != "tcp" {
+			add(prefix+".tls", "only applies to tcp:// hosts, but %s.host is %q", prefix, host)
+		}
+	}
+	if tls.Enabled() && host == "" {
+		add(prefix+".tls", "requires a tcp:// %s.host", prefix)
+	}
+	if (tls.Cert == "") != (tls.Key == "") {
+		add(prefix+".tls", "cert and key must be given together")
+	}
+	// The files are checked when connecting and by doctor, so a missing
+	// file for one host does not stop commands that never dial it.
+}
+
+// validateLabels checks each label in labels, reported under key[i]. seen
+// maps the labels already given, in lower case, to their keys, so labels
+// that repeat runners.labels are caught too.
+func validateLabels(add func(key, format string, args ...any), key string, labels []string, seen map[string]string) {
+	for i, label := range labels {
+		key := fmt.Sprintf("%s[%d]", key, i)
+		switch {
+		case strings.TrimSpace(label) == "":
+			add(key, "label must not be empty")
+		case strings.Contains(label, ","):
+			add(key, "label %q must not contain commas", label)
+		case strings.TrimSpace(label) != label:
+			add(key, "label %q must not start or end with whitespace", label)
+		case len(label) > maxLabelLength:
+			add(key, "label is longer than %d characters", maxLabelLength)
+		}
+		// GitHub compares labels case-insensitively.
+		if prev, ok := seen[strings.ToLower(label)]; ok {
+			add(key, "duplicate label %q (same as %s)", label, prev)
+		} else {
+			seen[strings.ToLower(label)] = key
+		}
+	}
+}
+
+// validateRepos checks the repos: list. Each repo's runners need a distinct
+// name prefix so their names do not clash.
+func validateRepos(add func(key, format string, args ...any), cfg *Config) {
+	if len(cfg.Repos) > 0 && cfg.Scope != "repo" {
+		add("repos", "requires scope 'repo', got %q", cfg.Scope)
+	}
+	names := make(map[string]int)
+	prefixes := make(map[string]int)
+	for i, r := range cfg.Repos {
+		key := fmt.Sprintf("repos[%d]", i)
+		if r.Owner == "" {
+			add(key+".owner", "is required")
+		}
+		if r.Name == "" {
+			add(key+".name", "is required")
+		}
+		if j, dup := names[strings.ToLower(r.FullName())]; dup {
+			add(key, "duplicate repo %s (same as repos[%d])", r.FullName(), j)
+		} else {
+			names[strings.ToLower(r.FullName())] = i
+		}
+		if r.Count < 0 {
+			add(key+".count", "must not be negative, got %d", r.Count)
+		}
+		prefix := r.PoolPrefix(cfg.Runners.NamePrefix)
+		sample := RunnerVars{Num: 1, Pool: r.Name}
+		// A prefix inherited from runners.name_prefix is reported there.
+		rendered, err := render(prefix, sample)
+		ok := err == nil
+		if r.NamePrefix != "" {
+			rendered, ok = renderSample(add, key+".name_prefix", prefix, sample)
+		}
+		switch j, dup := prefixes[prefix]; {
+		case !ok:
+		case !namePrefixRe.MatchString(rendered):
+			add(key+".name_prefix", "%q would produce invalid container names; use letters, digits, '_', '.' and '-', starting with a letter or digit", rendered)
+		case dup:
+			add(key+".name_prefix", "%q is already used by repos[%d]; each repo needs its own prefix", prefix, j)
+		default:
+			prefixes[prefix] = i
+		}
+		seen := make(map[string]string)
+		for j, label := range cfg.Runners.Labels {
+			seen[strings.ToLower(label)] = fmt.Sprintf("runners.labels[%d]", j)
+		}
+		validateLabels(add, key+".labels", renderLabels(add, key+".labels", r.Labels, sample), seen)
+	}
+}
+
+// sampleVars returns the values runner templates are checked with: those of
+// the first runner on the first host of the first repo.
+func sampleVars(cfg *Config) RunnerVars {
+	v := RunnerVars{Num: 1}
+	if len(cfg.Hosts) > 0 {
+		v.Host = cfg.Hosts[0].Name
+	}
+	if len(cfg.Repos) > 0 {
+		v.Pool = cfg.Repos[0].Name
+	}
+	return v
+}
+
+// renderSample executes s as a runner template with sample values and
+// reports a problem if that fails. It returns the result and whether it
+// can be checked further.
+func renderSample(add func(key, format string, args ...any), key, s string, v RunnerVars) (string, bool) {
+	out, err := render(s, v)
+	if err != nil {
+		add(key, "invalid template: %s", templateErrRe.ReplaceAllString(err.Error(), ""))
+		return s, false
+	}
+	return out, true
+}
+
+// renderLabels returns labels with templates executed with sample values,
+// for validateLabels. Templates that fail are reported; they and templates
+// that render empty, which drop the label, are kept as they are.
+func renderLabels(add func(key, format string, args ...any), key string, labels []string, v RunnerVars) []string {
+	out := append([]string(nil), labels...)
+	for i, l := range labels {
+		if !IsTemplate(l) {
+			continue
+		}
+		if r, ok := renderSample(add, fmt.Sprintf("%s[%d]", key, i), l, v); ok && strings.TrimSpace(r) != "" {
+			out[i] = r
+		}
+	}
+	return out
+}
+
+// validateGitHubURL checks that raw, if set, is an absolute http(s) URL.
+func validateGitHubURL(add func(key, format string, args ...any), key, raw string) {
+	if raw == "" {
+		return
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add(key, "invalid URL %q; expected e.g. https://ghe.example.com", raw)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// walkLayer records the position of every value in a parsed config file,
+// keyed like Problem.Key, and reports keys that match no config field.
+func walkLayer(path string, n *yaml.Node, t reflect.Type, prefix string, positions map[string]Position, problems *[]Problem) {
+	if n.Kind == yaml.DocumentNode {
+		if len(n.Content) == 0 {
+			return
+		}
+		n = n.Content[0]
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	pos := func(n *yaml.Node) Position {
+		return Position{File: path, Line: n.Line, Column: n.Column}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Value == "<<" {
+				continue
+			}
+			key := joinKey(prefix, k.Value)
+			f, ok := structField(t, k.Value)
+			if !ok {
+				msg := "unknown key"
+				if s := closest(k.Value, fieldNames(t)); s != "" {
+					msg += fmt.Sprintf("; did you mean %q?", joinKey(prefix, s))
+				}
+				*problems = append(*problems, Problem{Key: key, Message: msg, Source: path, Pos: pos(k)})
+				continue
+			}
+			positions[key] = pos(v)
+			walkLayer(path, v, f.Type, key, positions, problems)
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			positions[joinKey(prefix, n.Content[i].Value)] = pos(n.Content[i+1])
+		}
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, v := range n.Content {
+			key := fmt.Sprintf("%s[%d]", prefix, i)
+			positions[key] = pos(v)
+			walkLayer(path, v, t.Elem(), key, positions, problems)
+		}
+	}
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// structField returns the field of struct type t whose yaml tag name is name.
+func structField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if tag == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func fieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); tag != "" && tag != "-" {
+			names = append(names, tag)
+		}
+	}
+	return names
+}
+
+// closest returns the candidate within edit distance 2 of name, or "".
+func closest(name string, candidates []string) string {
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := editDistance(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
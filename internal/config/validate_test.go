@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadedValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `scope: org
+org: acme
+token: env:GH_TOKEN
+runners:
+  lables: [gpu]
+  labels:
+    - linux
+    - "a,b"
+  extra_env:
+    RUNNER_NAME: custom
+docker:
+  restart_policy: sometimes
+schedules:
+  - cron: "0 8 * * *"
+    count: 2
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GHR_RUNNERS_NAME_PREFIX", "bad prefix")
+
+	loaded, err := LoadLayers(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	err = loaded.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+
+	want := []string{
+		path + `:5:3: runners.lables: unknown key; did you mean "runners.labels"?`,
+		path + `:8:7: runners.labels[1]: label "a,b" must not contain commas`,
+		path + `:10:18: runners.extra_env.RUNNER_NAME: RUNNER_NAME is set by ghr and cannot be overridden`,
+		path + `:12:19: docker.restart_policy: invalid value "sometimes"`,
+		path + `:14:5: schedules[0]: timezone is required`,
+		`runners.name_prefix: "bad prefix" would produce invalid container names`,
+	}
+	if len(verr.Problems) != len(want) {
+		t.Fatalf("got %d problems, want %d:\n%v", len(verr.Problems), len(want), err)
+	}
+	for i, p := range verr.Problems {
+		if !strings.HasPrefix(p.String(), want[i]) {
+			t.Errorf("problem %d = %q, want prefix %q", i, p.String(), want[i])
+		}
+	}
+	if src := verr.Problems[5].Source; src != "env GHR_RUNNERS_NAME_PREFIX" {
+		t.Errorf("name_prefix source = %q", src)
+	}
+}
+
+func TestLoadedValidateValid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("org: acme\nrunners:\n  labels: [linux, gpu]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadLayers(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLayers() error = %v", err)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"labels", "labels", 0},
+		{"lables", "labels", 2},
+		{"count", "cont", 1},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValidateKey(t *testing.T) {
+	cfg := Default()
+	cfg.Runners.Labels = []string{"ok", ""}
+	if err := ValidateKey(cfg, "runners.labels"); err == nil {
+		t.Error("ValidateKey(runners.labels) expected error for empty label")
+	}
+	// org is missing, but that is not a problem with scope.
+	if err := ValidateKey(cfg, "scope"); err != nil {
+		t.Errorf("ValidateKey(scope) error = %v", err)
+	}
+	if err := ValidateKey(cfg, "org"); err == nil {
+		t.Error("ValidateKey(org) expected error for missing org")
+	}
+}
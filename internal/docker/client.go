@@ -2,89 +2,80 @@ package docker
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"net/http"
+	"sync"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
 )
 
-// Client wraps the Docker Engine SDK.
+// Client wraps the Docker Engine SDK. The daemon may also be Podman's
+// Docker-compatible API service; see Podman.
 type Client struct {
 	cli *client.Client
-}
-
-// NewClient creates a Docker client using the given socket path.
-// If host is the default "/var/run/docker.sock", it also checks the active
-// Docker CLI context (e.g. OrbStack, colima) for the real endpoint.
-func NewClient(host string) (*Client, error) {
-	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
-
-	// If DOCKER_HOST is already set, FromEnv handles it.
-	// Otherwise, try to resolve from the active docker context.
-	if os.Getenv("DOCKER_HOST") == "" {
-		if endpoint := resolveDockerContext(); endpoint != "" {
-			opts = append(opts, client.WithHost(endpoint))
-		} else if host != "" {
-			opts = append(opts, client.WithHost("unix://"+host))
-		}
-	}
+	// socket is the local socket path the client connects to, or "".
+	socket string
 
-	cli, err := client.NewClientWithOpts(opts...)
-	if err != nil {
-		return nil, fmt.Errorf("creating docker client: %w", err)
-	}
-	return &Client{cli: cli}, nil
+	mu     sync.Mutex
+	podman *bool
 }
 
-// resolveDockerContext reads ~/.docker/config.json to find the current context,
-// then reads the context metadata to extract the Docker endpoint.
-func resolveDockerContext() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-	configPath := filepath.Join(home, ".docker", "config.json")
-	data, err := os.ReadFile(configPath)
+// NewClient creates a Docker client for the endpoint ResolveEndpoint picks
+// from conf: a tcp:// host with optional TLS client certificates, an
+// ssh://user@host reached through the ssh command, a unix socket (including
+// a rootless Podman socket), or the active Docker CLI context (e.g.
+// OrbStack, colima).
+func NewClient(conf config.DockerConf) (*Client, error) {
+	ep, err := ResolveEndpoint(conf)
 	if err != nil {
-		return ""
+		return nil, err
 	}
 
-	var dockerConfig struct {
-		CurrentContext string `json:"currentContext"`
-	}
-	if err := json.Unmarshal(data, &dockerConfig); err != nil || dockerConfig.CurrentContext == "" {
-		return ""
-	}
-	if dockerConfig.CurrentContext == "default" {
-		return ""
+	var opts []client.Opt
+	switch {
+	case ep.SSH():
+		// Checked first, as the SDK cannot dial ssh:// hosts from any source.
+		dial, err := sshDialer(ep.Host)
+		if err != nil {
+			return nil, err
+		}
+		// The host name is not used; every connection goes through ssh.
+		opts = append(opts, client.WithVersionFromEnv(), client.WithHost("http://docker.example.com"), client.WithDialContext(dial))
+	case ep.Source == SourceDockerHost || ep.Source == SourceDefault:
+		// FromEnv handles DOCKER_HOST and its DOCKER_CERT_PATH/DOCKER_TLS_VERIFY.
+		opts = append(opts, client.FromEnv)
+	default:
+		opts = append(opts, client.WithVersionFromEnv())
+		if err := ep.CheckTLSFiles(); err != nil {
+			return nil, err
+		}
+		if ep.TLS != nil {
+			tlsConf, err := tlsconfig.Client(tlsconfig.Options{
+				CAFile:             ep.TLS.CA,
+				CertFile:           ep.TLS.Cert,
+				KeyFile:            ep.TLS.Key,
+				InsecureSkipVerify: ep.TLS.SkipVerify,
+				ExclusiveRootPools: true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("loading TLS config for %s: %w", ep.Host, err)
+			}
+			opts = append(opts, client.WithHTTPClient(&http.Client{Transport: &http.Transport{TLSClientConfig: tlsConf}}))
+		}
+		opts = append(opts, client.WithHost(ep.Host))
 	}
+	opts = append(opts, client.WithAPIVersionNegotiation())
 
-	// Docker CLI hashes the context name with SHA256 for the directory name
-	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(dockerConfig.CurrentContext)))
-	metaPath := filepath.Join(home, ".docker", "contexts", "meta", hash, "meta.json")
-	metaData, err := os.ReadFile(metaPath)
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
-		return ""
-	}
-
-	var meta struct {
-		Endpoints map[string]struct {
-			Host string `json:"Host"`
-		} `json:"Endpoints"`
-	}
-	if err := json.Unmarshal(metaData, &meta); err != nil {
-		return ""
-	}
-	if ep, ok := meta.Endpoints["docker"]; ok && ep.Host != "" {
-		return ep.Host
+		return nil, fmt.Errorf("creating docker client: %w", err)
 	}
-	return ""
+	return &Client{cli: cli, socket: ep.SocketPath()}, nil
 }
 
 // Close closes the underlying Docker client.
@@ -104,12 +95,39 @@ func (c *Client) PullImage(ctx context.Context, ref string) error {
 	return nil
 }
 
-// ContainerLogs returns a reader for the container's logs.
-func (c *Client) ContainerLogs(ctx context.Context, containerID string, follow bool) (io.ReadCloser, error) {
+// LogOptions controls which container log output is returned.
+type LogOptions struct {
+	Follow     bool
+	Timestamps bool
+	Tail       string // number of lines from the end, or "all"; defaults to "100"
+	Since      string // RFC 3339 timestamp, Unix timestamp or relative duration (e.g. "10m")
+	Until      string // same formats as Since
+}
+
+// ContainerLogs returns a reader for the container's logs. For containers
+// created without a TTY the stream is multiplexed; see ContainerTTY.
+func (c *Client) ContainerLogs(ctx context.Context, containerID string, opts LogOptions) (io.ReadCloser, error) {
+	tail := opts.Tail
+	if tail == "" {
+		tail = "100"
+	}
 	return c.cli.ContainerLogs(ctx, containerID, container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
-		Follow:     follow,
-		Tail:       "100",
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+		Tail:       tail,
+		Since:      opts.Since,
+		Until:      opts.Until,
 	})
 }
+
+// ContainerTTY reports whether the container was created with a TTY, in
+// which case its log stream is raw rather than multiplexed.
+func (c *Client) ContainerTTY(ctx context.Context, containerID string) (bool, error) {
+	info, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return false, fmt.Errorf("inspecting container %s: %w", containerID, err)
+	}
+	return info.Config != nil && info.Config.Tty, nil
+}
@@ -0,0 +1,283 @@
+// Package dockertest provides an in-memory container runtime for tests that
+// would otherwise need a Docker daemon.
+package dockertest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+)
+
+// Fake is an in-memory stand-in for a Docker host that implements
+// runner.Runtime. Containers are kept in creation order and get unique
+// 12-character IDs that start with a letter. It is safe for concurrent use.
+type Fake struct {
+	// Err, if set, is called before every operation with the operation name
+	// ("create", "list", "start", "stop", "remove", "inspect", "logs",
+	// "pull") and the container name, container reference or image it acts
+	// on ("" for list). A non-nil result fails the operation with that error
+	// and leaves the containers unchanged.
+	Err func(op, target string) error
+	// Logs holds the log output returned for each container name.
+	Logs map[string]string
+
+	mu         sync.Mutex
+	containers []docker.RunnerContainer
+	pulled     []string
+	closed     bool
+}
+
+// New returns a Fake with no containers.
+func New() *Fake {
+	return &Fake{Logs: make(map[string]string)}
+}
+
+// Add puts a container on the host as if another ghr run had created it. An
+// empty ID is filled in; an empty State means "running".
+func (f *Fake) Add(c docker.RunnerContainer) docker.RunnerContainer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c.ID == "" {
+		c.ID = f.newID()
+	}
+	if c.State == "" {
+		c.State = "running"
+	}
+	if c.Status == "" {
+		c.Status = statusFor(c.State)
+	}
+	f.containers = append(f.containers, c)
+	return c
+}
+
+// Containers returns a copy of the containers on the host, ordered by
+// runner number.
+func (f *Fake) Containers() []docker.RunnerContainer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := append([]docker.RunnerContainer(nil), f.containers...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Num < list[j].Num })
+	return list
+}
+
+// Nums returns the runner numbers on the host, in ascending order.
+func (f *Fake) Nums() []int {
+	var nums []int
+	for _, c := range f.Containers() {
+		nums = append(nums, c.Num)
+	}
+	return nums
+}
+
+// Pulled returns the images pulled so far, in order.
+func (f *Fake) Pulled() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.pulled...)
+}
+
+// Closed reports whether Close was called.
+func (f *Fake) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// CreateRunner adds a running runner container named like a real one. It
+// fails if a container with that name exists, as Docker does.
+func (f *Fake) CreateRunner(ctx context.Context, cfg *config.Config, num, index int, token string) (string, error) {
+	name := fmt.Sprintf("%s-runner-%d", cfg.Runners.NamePrefix, num)
+	if err := f.fail("create", name); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.find(name); ok {
+		return "", fmt.Errorf("creating container %s: Conflict. The container name %q is already in use", name, "/"+name)
+	}
+	c := docker.RunnerContainer{
+		ID:     f.newID(),
+		Name:   name,
+		Num:    num,
+		State:  "running",
+		Status: statusFor("running"),
+		Labels: docker.ManagedLabels(cfg, num),
+	}
+	c.Labels[docker.LabelRunnerIndex] = strconv.Itoa(index)
+	f.containers = append(f.containers, c)
+	return c.ID, nil
+}
+
+// ListManagedContainers returns the containers owned by cfg, in creation
+// order.
+func (f *Fake) ListManagedContainers(ctx context.Context, cfg *config.Config) ([]docker.RunnerContainer, error) {
+	if err := f.fail("list", ""); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var list []docker.RunnerContainer
+	for _, c := range f.containers {
+		if docker.Owns(cfg, c.Labels) {
+			list = append(list, c)
+		}
+	}
+	return list, nil
+}
+
+// StartRunner marks the container running.
+func (f *Fake) StartRunner(ctx context.Context, idOrName string) error {
+	return f.setState("start", idOrName, "running")
+}
+
+// StopRunner marks the container exited.
+func (f *Fake) StopRunner(ctx context.Context, idOrName string) error {
+	return f.setState("stop", idOrName, "exited")
+}
+
+// RemoveRunner removes the container, running or not.
+func (f *Fake) RemoveRunner(ctx context.Context, idOrName string) error {
+	if err := f.fail("remove", idOrName); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, ok := f.find(idOrName)
+	if !ok {
+		return notFound(idOrName)
+	}
+	f.containers = append(f.containers[:i], f.containers[i+1:]...)
+	return nil
+}
+
+// InspectRunner returns the container's ID, name, state and labels.
+func (f *Fake) InspectRunner(ctx context.Context, idOrName string) (types.ContainerJSON, error) {
+	if err := f.fail("inspect", idOrName); err != nil {
+		return types.ContainerJSON{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, ok := f.find(idOrName)
+	if !ok {
+		return types.ContainerJSON{}, notFound(idOrName)
+	}
+	c := f.containers[i]
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    c.ID,
+			Name:  "/" + c.Name,
+			State: &types.ContainerState{Status: c.State, Running: c.State == "running"},
+		},
+		Config: &container.Config{Labels: c.Labels, Tty: true},
+	}, nil
+}
+
+// ContainerLogs returns the container's entry in Logs.
+func (f *Fake) ContainerLogs(ctx context.Context, containerID string, opts docker.LogOptions) (io.ReadCloser, error) {
+	if err := f.fail("logs", containerID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, ok := f.find(containerID)
+	if !ok {
+		return nil, notFound(containerID)
+	}
+	return io.NopCloser(strings.NewReader(f.Logs[f.containers[i].Name])), nil
+}
+
+// ContainerTTY reports true, so logs are read as a raw stream.
+func (f *Fake) ContainerTTY(ctx context.Context, containerID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.find(containerID); !ok {
+		return false, notFound(containerID)
+	}
+	return true, nil
+}
+
+// PullImage records the image as pulled.
+func (f *Fake) PullImage(ctx context.Context, ref string) error {
+	if err := f.fail("pull", ref); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pulled = append(f.pulled, ref)
+	return nil
+}
+
+// Close marks the fake closed.
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *Fake) fail(op, target string) error {
+	if f.Err == nil {
+		return nil
+	}
+	return f.Err(op, target)
+}
+
+func (f *Fake) setState(op, idOrName, state string) error {
+	if err := f.fail(op, idOrName); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i, ok := f.find(idOrName)
+	if !ok {
+		return notFound(idOrName)
+	}
+	f.containers[i].State = state
+	f.containers[i].Status = statusFor(state)
+	return nil
+}
+
+// find returns the index of the container with the given name or ID prefix.
+// f.mu must be held.
+func (f *Fake) find(idOrName string) (int, bool) {
+	for i, c := range f.containers {
+		if c.Name == idOrName || (idOrName != "" && strings.HasPrefix(c.ID, idOrName)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// lastID numbers the containers of all fakes, so IDs are unique across hosts.
+var lastID atomic.Int64
+
+// newID returns a new container ID, 12 hex digits like a short Docker ID.
+// IDs start with a letter so that a runner number is never also an ID
+// prefix.
+func (f *Fake) newID() string {
+	sum := sha256.Sum256([]byte(strconv.FormatInt(lastID.Add(1), 10)))
+	return "f" + hex.EncodeToString(sum[:6])[1:]
+}
+
+func statusFor(state string) string {
+	if state == "running" {
+		return "Up 1 second"
+	}
+	return "Exited (0) 1 second ago"
+}
+
+func notFound(ref string) error {
+	return fmt.Errorf("Error response from daemon: No such container: %s", ref)
+}
@@ -0,0 +1,216 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+)
+
+// Sources of the Docker endpoint, as reported by ResolveEndpoint.
+const (
+	SourceHost       = "docker.host"
+	SourceDockerHost = "DOCKER_HOST"
+	SourceContext    = "docker context"
+	SourceSocket     = "docker.socket"
+	SourcePodman     = "podman socket"
+	SourceDefault    = "default"
+)
+
+// Endpoint is the Docker host NewClient connects to.
+type Endpoint struct {
+	Host   string
+	Source string
+	// Context is the Docker CLI context the host came from, if any.
+	Context string
+	// TLS holds the client certificate files for a tcp:// host, or nil.
+	TLS *config.DockerTLSConf
+}
+
+// SSH reports whether the host is reached over ssh.
+func (e Endpoint) SSH() bool {
+	return strings.HasPrefix(e.Host, "ssh://")
+}
+
+// SocketPath returns the path of a unix:// host, or "".
+func (e Endpoint) SocketPath() string {
+	if strings.HasPrefix(e.Host, "unix://") {
+		return strings.TrimPrefix(e.Host, "unix://")
+	}
+	return ""
+}
+
+// CheckTLSFiles returns an error naming the first of the endpoint's TLS
+// files that cannot be read.
+func (e Endpoint) CheckTLSFiles() error {
+	if e.TLS == nil {
+		return nil
+	}
+	for _, f := range []struct{ name, path string }{
+		{"CA", e.TLS.CA}, {"cert", e.TLS.Cert}, {"key", e.TLS.Key},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			return fmt.Errorf("TLS %s file for %s: %w", f.name, e.Host, err)
+		}
+	}
+	return nil
+}
+
+// String describes the endpoint and where it came from.
+func (e Endpoint) String() string {
+	if e.Context != "" {
+		return fmt.Sprintf("%s (from %s %q)", e.Host, e.Source, e.Context)
+	}
+	return fmt.Sprintf("%s (from %s)", e.Host, e.Source)
+}
+
+// ResolveEndpoint returns the Docker endpoint for conf. In order of priority
+// it is docker.host, the Docker CLI context named by docker.context,
+// DOCKER_HOST, the DOCKER_CONTEXT or current Docker CLI context, the socket
+// path, or the SDK default. When the socket path does not exist but a Podman
+// API socket does, as on hosts without Docker, the Podman socket is used.
+func ResolveEndpoint(conf config.DockerConf) (Endpoint, error) {
+	if conf.Host != "" {
+		ep := Endpoint{Host: conf.Host, Source: SourceHost}
+		if conf.TLS.Enabled() {
+			tls := conf.TLS
+			ep.TLS = &tls
+		}
+		return ep, nil
+	}
+	if conf.Context != "" {
+		ep, err := contextEndpoint(conf.Context)
+		if err != nil {
+			return Endpoint{}, fmt.Errorf("docker.context: %w", err)
+		}
+		return ep, nil
+	}
+	if h := os.Getenv("DOCKER_HOST"); h != "" {
+		return Endpoint{Host: h, Source: SourceDockerHost}, nil
+	}
+	if name := currentContext(); name != "" && name != "default" {
+		if ep, err := contextEndpoint(name); err == nil {
+			return ep, nil
+		}
+	}
+	if conf.Socket != "" {
+		if existing(conf.Socket) == "" {
+			if sock := podmanSocket(); sock != "" {
+				return Endpoint{Host: "unix://" + sock, Source: SourcePodman}, nil
+			}
+		}
+		return Endpoint{Host: "unix://" + conf.Socket, Source: SourceSocket}, nil
+	}
+	return Endpoint{Host: client.DefaultDockerHost, Source: SourceDefault}, nil
+}
+
+// podmanSocket returns the first Podman API socket that exists: the rootless
+// $XDG_RUNTIME_DIR/podman/podman.sock, then the rootful
+// /run/podman/podman.sock. It returns "" if there is none.
+func podmanSocket() string {
+	var candidates []string
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, "podman", "podman.sock"))
+	}
+	candidates = append(candidates, rootfulPodmanSocket)
+	for _, path := range candidates {
+		if existing(path) != "" {
+			return path
+		}
+	}
+	return ""
+}
+
+// rootfulPodmanSocket is the socket of the system-wide Podman API service.
+// A variable so tests can point it elsewhere.
+var rootfulPodmanSocket = "/run/podman/podman.sock"
+
+// dockerConfigDir returns the Docker CLI config directory: $DOCKER_CONFIG or
+// ~/.docker.
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker")
+}
+
+// currentContext returns the Docker CLI context selected by DOCKER_CONTEXT
+// or the currentContext field of config.json, or "".
+func currentContext() string {
+	if name := os.Getenv("DOCKER_CONTEXT"); name != "" {
+		return name
+	}
+	data, err := os.ReadFile(filepath.Join(dockerConfigDir(), "config.json"))
+	if err != nil {
+		return ""
+	}
+	var dockerConfig struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(data, &dockerConfig); err != nil {
+		return ""
+	}
+	return dockerConfig.CurrentContext
+}
+
+// contextEndpoint reads the Docker endpoint of a Docker CLI context from its
+// metadata, together with the TLS files stored for it.
+func contextEndpoint(name string) (Endpoint, error) {
+	// Docker CLI hashes the context name with SHA256 for the directory names.
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+	dir := filepath.Join(dockerConfigDir(), "contexts")
+	metaData, err := os.ReadFile(filepath.Join(dir, "meta", hash, "meta.json"))
+	if os.IsNotExist(err) {
+		return Endpoint{}, fmt.Errorf("Docker context %q not found; see `docker context ls`", name)
+	}
+	if err != nil {
+		return Endpoint{}, fmt.Errorf("reading Docker context %q: %w", name, err)
+	}
+
+	var meta struct {
+		Endpoints map[string]struct {
+			Host          string `json:"Host"`
+			SkipTLSVerify bool   `json:"SkipTLSVerify"`
+		} `json:"Endpoints"`
+	}
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return Endpoint{}, fmt.Errorf("parsing Docker context %q: %w", name, err)
+	}
+	ep, ok := meta.Endpoints["docker"]
+	if !ok || ep.Host == "" {
+		return Endpoint{}, fmt.Errorf("Docker context %q has no Docker endpoint", name)
+	}
+
+	result := Endpoint{Host: ep.Host, Source: SourceContext, Context: name}
+	tlsDir := filepath.Join(dir, "tls", hash, "docker")
+	tls := config.DockerTLSConf{
+		CA:         existing(filepath.Join(tlsDir, "ca.pem")),
+		Cert:       existing(filepath.Join(tlsDir, "cert.pem")),
+		Key:        existing(filepath.Join(tlsDir, "key.pem")),
+		SkipVerify: ep.SkipTLSVerify,
+	}
+	if tls.Enabled() && strings.HasPrefix(ep.Host, "tcp://") {
+		result.TLS = &tls
+	}
+	return result, nil
+}
+
+// existing returns path if it exists, or "".
+func existing(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
@@ -4,36 +4,82 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/api/types/filters"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
 )
 
 const (
-	LabelManaged   = "dev.ghr.managed"
-	LabelRunnerNum = "dev.ghr.runner-num"
-	LabelScope     = "dev.ghr.scope"
-	LabelOrg       = "dev.ghr.org"
-	LabelRepoOwner = "dev.ghr.repo-owner"
-	LabelRepoName  = "dev.ghr.repo-name"
+	LabelManaged    = "dev.ghr.managed"
+	LabelRunnerNum  = "dev.ghr.runner-num"
+	LabelScope      = "dev.ghr.scope"
+	LabelOrg        = "dev.ghr.org"
+	LabelRepoOwner  = "dev.ghr.repo-owner"
+	LabelRepoName   = "dev.ghr.repo-name"
+	LabelEnterprise = "dev.ghr.enterprise"
+
+	// LabelRunnerIndex holds the runner's {{.Index}}, so a later `ghr up`
+	// can reuse the indexes of removed runners without duplicating others.
+	LabelRunnerIndex = "dev.ghr.runner-index"
 )
 
-// ManagedLabels returns the base labels for a ghr-managed container.
-func ManagedLabels(scope, org, repoOwner, repoName string, num int) map[string]string {
+// ManagedLabels returns the base labels for a ghr-managed container of
+// runner num under cfg's scope.
+func ManagedLabels(cfg *config.Config, num int) map[string]string {
 	labels := map[string]string{
 		LabelManaged:   "true",
 		LabelRunnerNum: fmt.Sprintf("%d", num),
-		LabelScope:     scope,
+		LabelScope:     cfg.Scope,
 	}
-	if scope == "org" {
-		labels[LabelOrg] = org
-	} else {
-		labels[LabelRepoOwner] = repoOwner
-		labels[LabelRepoName] = repoName
+	switch cfg.Scope {
+	case "org":
+		labels[LabelOrg] = cfg.Org
+	case "repo":
+		labels[LabelRepoOwner] = cfg.Repo.Owner
+		labels[LabelRepoName] = cfg.Repo.Name
+	case "enterprise":
+		labels[LabelEnterprise] = cfg.Enterprise
 	}
 	return labels
 }
 
-// ManagedFilter returns a Docker filter that matches ghr-managed containers.
-func ManagedFilter() filters.Args {
-	return filters.NewArgs(
+// ManagedFilter returns a Docker filter that matches the ghr-managed
+// containers of cfg's scope and org, repository or enterprise, so configs
+// for different targets can share a Docker host. With a repos: list it
+// matches the containers of every repository; Owns narrows them down.
+func ManagedFilter(cfg *config.Config) filters.Args {
+	args := filters.NewArgs(
 		filters.Arg("label", LabelManaged+"=true"),
+		filters.Arg("label", LabelScope+"="+cfg.Scope),
 	)
+	switch cfg.Scope {
+	case "org":
+		args.Add("label", LabelOrg+"="+cfg.Org)
+	case "enterprise":
+		args.Add("label", LabelEnterprise+"="+cfg.Enterprise)
+	case "repo":
+		if len(cfg.Repos) == 0 {
+			args.Add("label", LabelRepoOwner+"="+cfg.Repo.Owner)
+			args.Add("label", LabelRepoName+"="+cfg.Repo.Name)
+		}
+	}
+	return args
+}
+
+// Owns reports whether a container with the given labels is a ghr-managed
+// runner of cfg's scope and target, including every repo in repos:.
+func Owns(cfg *config.Config, labels map[string]string) bool {
+	if labels[LabelManaged] != "true" || labels[LabelScope] != cfg.Scope {
+		return false
+	}
+	switch cfg.Scope {
+	case "org":
+		return labels[LabelOrg] == cfg.Org
+	case "enterprise":
+		return labels[LabelEnterprise] == cfg.Enterprise
+	}
+	for _, p := range cfg.Pools() {
+		if labels[LabelRepoOwner] == p.Repo.Owner && labels[LabelRepoName] == p.Repo.Name {
+			return true
+		}
+	}
+	return false
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
 )
 
@@ -20,12 +21,12 @@ type RunnerContainer struct {
 	State  string // running, exited, created, etc.
 	Status string // human-readable status from Docker
 	Labels map[string]string
+	Host   string // fleet host name, set by runner.Manager
 }
 
-// CreateRunner creates and starts a new runner container.
-func (c *Client) CreateRunner(ctx context.Context, cfg *config.Config, num int, token string) (string, error) {
-	name := fmt.Sprintf("%s-runner-%d", cfg.Runners.NamePrefix, num)
-
+// runnerEnv returns the environment of the runner container name, which
+// tells the runner image where to register.
+func runnerEnv(cfg *config.Config, name, token string) []string {
 	env := []string{
 		"RUNNER_SCOPE=" + cfg.Scope,
 		"RUNNER_NAME=" + name,
@@ -33,10 +34,17 @@ func (c *Client) CreateRunner(ctx context.Context, cfg *config.Config, num int,
 		"RUNNER_GROUP=" + cfg.Runners.Group,
 		"ACCESS_TOKEN=" + token,
 	}
-	if cfg.Scope == "org" {
+	switch cfg.Scope {
+	case "org":
 		env = append(env, "ORG_NAME="+cfg.Org)
-	} else {
-		env = append(env, "REPO_URL=https://github.com/"+cfg.Repo.Owner+"/"+cfg.Repo.Name)
+	case "enterprise":
+		env = append(env, "ENTERPRISE_NAME="+cfg.Enterprise)
+	default:
+		env = append(env, "REPO_URL="+cfg.GitHub.WebURL()+"/"+cfg.Repo.Owner+"/"+cfg.Repo.Name)
+	}
+	if cfg.GitHub.BaseURL != "" {
+		// The runner image registers against GITHUB_HOST instead of github.com.
+		env = append(env, "GITHUB_HOST="+cfg.GitHub.WebHost())
 	}
 	if cfg.Runners.Ephemeral {
 		env = append(env, "EPHEMERAL=true")
@@ -44,14 +52,34 @@ func (c *Client) CreateRunner(ctx context.Context, cfg *config.Config, num int,
 	for k, v := range cfg.Runners.ExtraEnv {
 		env = append(env, k+"="+v)
 	}
+	return env
+}
 
-	labels := ManagedLabels(cfg.Scope, cfg.Org, cfg.Repo.Owner, cfg.Repo.Name, num)
+// CreateRunner creates and starts a new runner container. On Podman the
+// work volume is created up front and SELinux labelling is disabled for
+// containers with bind mounts, so the runner can use the mounted socket and
+// work directory on enforcing hosts such as RHEL. index is recorded in the
+// LabelRunnerIndex label.
+func (c *Client) CreateRunner(ctx context.Context, cfg *config.Config, num, index int, token string) (string, error) {
+	name := fmt.Sprintf("%s-runner-%d", cfg.Runners.NamePrefix, num)
+	podman, err := c.Podman(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	env := runnerEnv(cfg, name, token)
+	labels := ManagedLabels(cfg, num)
+	labels[LabelRunnerIndex] = strconv.Itoa(index)
 
 	var mounts []mount.Mount
 	if cfg.Docker.MountDockerSocket {
+		socket, err := c.MountSocket(ctx, cfg.Docker)
+		if err != nil {
+			return "", err
+		}
 		mounts = append(mounts, mount.Mount{
 			Type:   mount.TypeBind,
-			Source: cfg.Docker.Socket,
+			Source: socket,
 			Target: "/var/run/docker.sock",
 		})
 	}
@@ -64,15 +92,31 @@ func (c *Client) CreateRunner(ctx context.Context, cfg *config.Config, num int,
 			Target: "/home/runner/_work",
 		})
 	} else {
+		volName := name + "-work"
+		if podman {
+			// Older Podman releases do not create a named volume that is
+			// only referenced from Mounts. Creating an existing volume is
+			// a no-op on both Podman and Docker.
+			if _, err := c.cli.VolumeCreate(ctx, volume.CreateOptions{Name: volName, Labels: labels}); err != nil {
+				return "", fmt.Errorf("creating volume %s: %w", volName, err)
+			}
+		}
 		mounts = append(mounts, mount.Mount{
 			Type:   mount.TypeVolume,
-			Source: name + "-work",
+			Source: volName,
 			Target: "/home/runner/_work",
 		})
 	}
 
 	restartPolicy := container.RestartPolicy{Name: container.RestartPolicyMode(cfg.Docker.RestartPolicy)}
 
+	var securityOpt []string
+	for _, m := range mounts {
+		if podman && m.Type == mount.TypeBind {
+			securityOpt = []string{"label=disable"}
+		}
+	}
+
 	resp, err := c.cli.ContainerCreate(ctx,
 		&container.Config{
 			Image:  cfg.Runners.Image,
@@ -82,6 +126,7 @@ func (c *Client) CreateRunner(ctx context.Context, cfg *config.Config, num int,
 		&container.HostConfig{
 			Mounts:        mounts,
 			RestartPolicy: restartPolicy,
+			SecurityOpt:   securityOpt,
 		},
 		nil, nil, name,
 	)
@@ -96,11 +141,12 @@ func (c *Client) CreateRunner(ctx context.Context, cfg *config.Config, num int,
 	return resp.ID, nil
 }
 
-// ListManagedContainers returns all ghr-managed containers (including stopped).
-func (c *Client) ListManagedContainers(ctx context.Context) ([]RunnerContainer, error) {
+// ListManagedContainers returns the ghr-managed containers of cfg's scope
+// and target (including stopped).
+func (c *Client) ListManagedContainers(ctx context.Context, cfg *config.Config) ([]RunnerContainer, error) {
 	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
 		All:     true,
-		Filters: ManagedFilter(),
+		Filters: ManagedFilter(cfg),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("listing containers: %w", err)
@@ -108,6 +154,9 @@ func (c *Client) ListManagedContainers(ctx context.Context) ([]RunnerContainer,
 
 	var runners []RunnerContainer
 	for _, ctr := range containers {
+		if !Owns(cfg, ctr.Labels) {
+			continue
+		}
 		num, _ := strconv.Atoi(ctr.Labels[LabelRunnerNum])
 		name := ""
 		if len(ctr.Names) > 0 {
@@ -0,0 +1,69 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+)
+
+func TestRunnerEnv(t *testing.T) {
+	base := func() *config.Config {
+		cfg := config.Default()
+		cfg.Runners.Labels = []string{"self-hosted", "linux"}
+		cfg.Runners.Ephemeral = false
+		return cfg
+	}
+	tests := []struct {
+		name  string
+		setup func(*config.Config)
+		want  []string
+	}{
+		{
+			name: "org",
+			setup: func(cfg *config.Config) {
+				cfg.Scope, cfg.Org = "org", "myorg"
+			},
+			want: []string{"ORG_NAME=myorg"},
+		},
+		{
+			name: "repo",
+			setup: func(cfg *config.Config) {
+				cfg.Scope, cfg.Repo = "repo", config.RepoConfig{Owner: "me", Name: "app"}
+			},
+			want: []string{"REPO_URL=https://github.com/me/app"},
+		},
+		{
+			name: "repo on GHES",
+			setup: func(cfg *config.Config) {
+				cfg.Scope, cfg.Repo = "repo", config.RepoConfig{Owner: "me", Name: "app"}
+				cfg.GitHub.BaseURL = "https://ghe.example.com/api/v3/"
+			},
+			want: []string{"REPO_URL=https://ghe.example.com/me/app", "GITHUB_HOST=ghe.example.com"},
+		},
+		{
+			name: "enterprise",
+			setup: func(cfg *config.Config) {
+				cfg.Scope, cfg.Enterprise = "enterprise", "acme"
+			},
+			want: []string{"ENTERPRISE_NAME=acme"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base()
+			tt.setup(cfg)
+			env := runnerEnv(cfg, "ghr-runner-1", "tok")
+			want := append([]string{
+				"RUNNER_SCOPE=" + cfg.Scope,
+				"RUNNER_NAME=ghr-runner-1",
+				"RUNNER_LABELS=self-hosted,linux",
+				"RUNNER_GROUP=" + cfg.Runners.Group,
+				"ACCESS_TOKEN=tok",
+			}, tt.want...)
+			if !reflect.DeepEqual(env, want) {
+				t.Errorf("runnerEnv() = %v, want %v", env, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sshDialer returns a dialer for an ssh://[user@]host[:port] Docker host.
+// Like the Docker CLI, each connection runs `docker system dial-stdio` on the
+// remote host with the local ssh command, so ~/.ssh/config, keys and the
+// SSH agent all apply.
+func sshDialer(host string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	args, err := sshArgs(host)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return newCommandConn("ssh", args...)
+	}, nil
+}
+
+// sshArgs returns the ssh arguments that connect to the Docker daemon on host.
+func sshArgs(host string) ([]string, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh host %q: %w", host, err)
+	}
+	if u.Scheme != "ssh" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ssh host %q; expected ssh://[user@]host[:port]", host)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return nil, fmt.Errorf("invalid ssh host %q: paths are not supported", host)
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, fmt.Errorf("invalid ssh host %q: passwords are not supported; use a key or the SSH agent", host)
+	}
+
+	args := []string{"-o", "ConnectTimeout=30", "-T"}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	target := u.Hostname()
+	if u.User != nil {
+		target = u.User.Username() + "@" + target
+	}
+	return append(args, "--", target, "docker", "system", "dial-stdio"), nil
+}
+
+// commandConn is a net.Conn over the stdin and stdout of a command.
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr *lockedBuffer
+
+	closeOnce sync.Once
+}
+
+func newCommandConn(name string, args ...string) (net.Conn, error) {
+	// Not tied to the dial context: the HTTP transport keeps connections
+	// open after the request that dialed them.
+	cmd := exec.Command(name, args...)
+	c := &commandConn{cmd: cmd, stderr: &lockedBuffer{}}
+	var err error
+	if c.stdin, err = cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+	if c.stdout, err = cmd.StdoutPipe(); err != nil {
+		return nil, err
+	}
+	cmd.Stderr = c.stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", name, err)
+	}
+	return c, nil
+}
+
+func (c *commandConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if err == io.EOF && n == 0 {
+		if stderr := c.stderrError(); stderr != nil {
+			return 0, stderr
+		}
+	}
+	return n, err
+}
+
+func (c *commandConn) Write(p []byte) (int, error) {
+	n, err := c.stdin.Write(p)
+	if err != nil {
+		if stderr := c.stderrError(); stderr != nil {
+			return n, stderr
+		}
+	}
+	return n, err
+}
+
+// stderrError returns what the command printed on stderr as an error, or nil.
+func (c *commandConn) stderrError() error {
+	msg := strings.TrimSpace(c.stderr.String())
+	if msg == "" {
+		return nil
+	}
+	// ssh already prefixes its own messages with "ssh: ".
+	if name := c.cmd.Args[0]; !strings.HasPrefix(msg, name+":") {
+		msg = name + ": " + msg
+	}
+	return errors.New(msg)
+}
+
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		if c.cmd.Process != nil {
+			c.cmd.Process.Kill()
+		}
+		c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr  { return dummyAddr{} }
+func (c *commandConn) RemoteAddr() net.Addr { return dummyAddr{} }
+
+// Deadlines are not supported on pipes; the HTTP client's own timeouts apply.
+func (c *commandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type dummyAddr struct{}
+
+func (dummyAddr) Network() string { return "ssh" }
+func (dummyAddr) String() string  { return "ssh" }
+
+// lockedBuffer is a bytes.Buffer safe for the concurrent writes from the
+// command and reads from the connection.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
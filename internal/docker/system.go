@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+)
+
+// MinAPIVersion is the oldest Docker Engine API version ghr works with. The
+// Mounts field used for the socket and work directory mounts needs 1.25.
+const MinAPIVersion = "1.25"
+
+// ServerVersion returns the Docker Engine and API version of the daemon.
+func (c *Client) ServerVersion(ctx context.Context) (types.Version, error) {
+	v, err := c.cli.ServerVersion(ctx)
+	if err != nil {
+		return types.Version{}, fmt.Errorf("connecting to docker: %w", err)
+	}
+	return v, nil
+}
+
+// IsPodman reports whether a version response comes from Podman's
+// Docker-compatible API service rather than Docker Engine.
+func IsPodman(v types.Version) bool {
+	for _, c := range v.Components {
+		if strings.HasPrefix(c.Name, "Podman") {
+			return true
+		}
+	}
+	return false
+}
+
+// Podman reports whether the daemon is Podman. The answer is cached after
+// the first successful call.
+func (c *Client) Podman(ctx context.Context) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.podman == nil {
+		v, err := c.ServerVersion(ctx)
+		if err != nil {
+			return false, err
+		}
+		podman := IsPodman(v)
+		c.podman = &podman
+	}
+	return *c.podman, nil
+}
+
+// MountSocket returns the host path of the socket mounted into runner
+// containers when docker.mount_docker_socket is set. This is docker.socket,
+// except with Podman on a local socket: there the socket ghr connects to is
+// mounted, since a rootless Podman socket lives under $XDG_RUNTIME_DIR.
+func (c *Client) MountSocket(ctx context.Context, conf config.DockerConf) (string, error) {
+	if c.socket == "" {
+		return conf.Socket, nil
+	}
+	podman, err := c.Podman(ctx)
+	if err != nil {
+		return "", err
+	}
+	if podman {
+		return c.socket, nil
+	}
+	return conf.Socket, nil
+}
+
+// APIVersionSupported reports whether the daemon API version is at least
+// MinAPIVersion.
+func APIVersionSupported(apiVersion string) bool {
+	return !versions.LessThan(apiVersion, MinAPIVersion)
+}
+
+// ImageExists reports whether the image is present on the Docker host.
+func (c *Client) ImageExists(ctx context.Context, ref string) (bool, error) {
+	_, _, err := c.cli.ImageInspectWithRaw(ctx, ref)
+	if client.IsErrNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("inspecting image %s: %w", ref, err)
+	}
+	return true, nil
+}
+
+// RegistryImageExists asks the image's registry, through the daemon,
+// whether the image exists. Private registries need `docker login` on the
+// Docker host.
+func (c *Client) RegistryImageExists(ctx context.Context, ref string) error {
+	if _, err := c.cli.DistributionInspect(ctx, ref, ""); err != nil {
+		return fmt.Errorf("looking up %s in registry: %w", ref, err)
+	}
+	return nil
+}
+
+// UnmanagedContainerNames returns the names of all containers (including
+// stopped ones) that are not managed by ghr.
+func (c *Client) UnmanagedContainerNames(ctx context.Context) ([]string, error) {
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+	var names []string
+	for _, ctr := range containers {
+		if ctr.Labels[LabelManaged] == "true" {
+			continue
+		}
+		for _, n := range ctr.Names {
+			names = append(names, strings.TrimPrefix(n, "/"))
+		}
+	}
+	return names, nil
+}
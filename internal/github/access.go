@@ -0,0 +1,86 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	gh "github.com/google/go-github/v68/github"
+)
+
+// TokenScopes returns the OAuth scopes granted to the token, read from the
+// X-OAuth-Scopes response header. ok is false for tokens that do not report
+// scopes, such as fine-grained personal access tokens and GitHub App tokens.
+func (c *Client) TokenScopes(ctx context.Context) (scopes []string, ok bool, err error) {
+	_, resp, err := c.gh.Users.Get(ctx, "")
+	if err != nil {
+		return nil, false, fmt.Errorf("authenticating: %w", err)
+	}
+	header, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		return nil, false, nil
+	}
+	for _, h := range header {
+		for _, s := range strings.Split(h, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	return scopes, true, nil
+}
+
+// RequiredScopes returns the classic token scopes needed to manage runners
+// for the given config scope ("org", "repo" or "enterprise").
+func RequiredScopes(scope string) []string {
+	switch scope {
+	case "org":
+		return []string{"admin:org"}
+	case "enterprise":
+		return []string{"manage_runners:enterprise"}
+	}
+	return []string{"repo"}
+}
+
+// MissingScopes returns the scopes in need that are not in have.
+func MissingScopes(have, need []string) []string {
+	granted := make(map[string]bool, len(have))
+	for _, s := range have {
+		granted[s] = true
+	}
+	var missing []string
+	for _, s := range need {
+		if !granted[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// CheckOrg verifies that the organization exists and is visible to the token.
+func (c *Client) CheckOrg(ctx context.Context, org string) error {
+	if _, _, err := c.gh.Organizations.Get(ctx, org); err != nil {
+		return fmt.Errorf("getting org %s: %w", org, err)
+	}
+	return nil
+}
+
+// CheckRepo verifies that the repository exists and is visible to the token.
+func (c *Client) CheckRepo(ctx context.Context, owner, repo string) error {
+	if _, _, err := c.gh.Repositories.Get(ctx, owner, repo); err != nil {
+		return fmt.Errorf("getting repo %s/%s: %w", owner, repo, err)
+	}
+	return nil
+}
+
+// StatusCode returns the HTTP status of a failed API call, or 0 if err is
+// not an API error.
+func StatusCode(err error) int {
+	var errResp *gh.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,28 @@
+package github
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		name  string
+		have  []string
+		scope string
+		want  []string
+	}{
+		{"org with admin:org", []string{"repo", "admin:org"}, "org", nil},
+		{"org with read:org only", []string{"read:org"}, "org", []string{"admin:org"}},
+		{"repo with repo", []string{"repo"}, "repo", nil},
+		{"repo with no scopes", nil, "repo", []string{"repo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MissingScopes(tt.have, RequiredScopes(tt.scope))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingScopes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
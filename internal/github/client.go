@@ -2,6 +2,9 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"strings"
 
 	gh "github.com/google/go-github/v68/github"
 	"golang.org/x/oauth2"
@@ -12,9 +15,41 @@ type Client struct {
 	gh *gh.Client
 }
 
-// NewClient creates a GitHub API client with the given token.
-func NewClient(ctx context.Context, token string) *Client {
+// NewClient creates a GitHub API client with the given token. apiURL is the
+// root of the REST API, such as https://ghe.example.com/api/v3/ or a
+// githubtest server's URL, and uploadURL the root of the upload API;
+// api.github.com and uploads.github.com are used when they are empty.
+func NewClient(ctx context.Context, token, apiURL, uploadURL string) (*Client, error) {
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	tc := oauth2.NewClient(ctx, ts)
-	return &Client{gh: gh.NewClient(tc)}
+	c := gh.NewClient(tc)
+	if apiURL != "" {
+		u, err := parseRoot(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub API URL %q", apiURL)
+		}
+		c.BaseURL = u
+		c.UploadURL = u
+	}
+	if uploadURL != "" {
+		u, err := parseRoot(uploadURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub upload URL %q", uploadURL)
+		}
+		c.UploadURL = u
+	}
+	return &Client{gh: c}, nil
+}
+
+// parseRoot parses an absolute API root URL and adds the trailing slash
+// go-github requires.
+func parseRoot(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("not an absolute URL")
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
 }
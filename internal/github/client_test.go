@@ -0,0 +1,397 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v68/github"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/github/githubtest"
+)
+
+func newTestClient(t *testing.T, srv *githubtest.Server, token string) *Client {
+	t.Helper()
+	c, err := NewClient(context.Background(), token, srv.URL, "")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return c
+}
+
+func TestNewClientBaseURL(t *testing.T) {
+	for _, u := range []string{"ghe.example.com", "://bad", "/api/v3"} {
+		if _, err := NewClient(context.Background(), "t", u, ""); err == nil {
+			t.Errorf("NewClient(%q) expected error", u)
+		}
+	}
+	if _, err := NewClient(context.Background(), "t", "https://ghe.example.com/api/v3/", "uploads"); err == nil {
+		t.Error("NewClient() with a relative upload URL expected error")
+	}
+	c, err := NewClient(context.Background(), "t", "https://ghe.example.com/api/v3", "")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if got := c.gh.BaseURL.String(); got != "https://ghe.example.com/api/v3/" {
+		t.Errorf("BaseURL = %q, want a trailing slash", got)
+	}
+	c, err = NewClient(context.Background(), "t", "https://ghe.example.com/api/v3/", "https://uploads.example.com/api/uploads")
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if got := c.gh.UploadURL.String(); got != "https://uploads.example.com/api/uploads/" {
+		t.Errorf("UploadURL = %q", got)
+	}
+}
+
+func TestEnterpriseServer(t *testing.T) {
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	conf := config.GitHubConf{BaseURL: srv.URL}
+	c, err := NewClient(context.Background(), "t", conf.APIURL(), conf.UploadAPIURL())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := c.CheckOrg(context.Background(), "myorg"); err != nil {
+		t.Fatalf("CheckOrg() error = %v", err)
+	}
+	if reqs := srv.Requests(); len(reqs) != 1 || reqs[0] != "GET /api/v3/orgs/myorg" {
+		t.Errorf("requests = %v, want GET /api/v3/orgs/myorg", reqs)
+	}
+}
+
+func TestTokenScopes(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.Token = "ghp_good"
+	srv.Scopes = []string{"repo", "admin:org"}
+
+	scopes, ok, err := newTestClient(t, srv, "ghp_good").TokenScopes(ctx)
+	if err != nil || !ok {
+		t.Fatalf("TokenScopes() = %v, %v, %v", scopes, ok, err)
+	}
+	if want := []string{"repo", "admin:org"}; !reflect.DeepEqual(scopes, want) {
+		t.Errorf("TokenScopes() = %v, want %v", scopes, want)
+	}
+
+	srv.Scopes = nil
+	if _, ok, err := newTestClient(t, srv, "ghp_good").TokenScopes(ctx); err != nil || ok {
+		t.Errorf("TokenScopes() without the header: ok = %v, err = %v, want false, nil", ok, err)
+	}
+
+	_, _, err = newTestClient(t, srv, "ghp_bad").TokenScopes(ctx)
+	if StatusCode(err) != http.StatusUnauthorized {
+		t.Errorf("TokenScopes() with a bad token: status %d, want 401 (err = %v)", StatusCode(err), err)
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddRepo("myorg/app", false)
+	c := newTestClient(t, srv, "t")
+
+	if err := c.CheckOrg(ctx, "myorg"); err != nil {
+		t.Errorf("CheckOrg() error = %v", err)
+	}
+	if err := c.CheckOrg(ctx, "other"); StatusCode(err) != http.StatusNotFound {
+		t.Errorf("CheckOrg(other) = %v, want 404", err)
+	}
+	if err := c.CheckRepo(ctx, "myorg", "app"); err != nil {
+		t.Errorf("CheckRepo() error = %v", err)
+	}
+	if err := c.CheckRepo(ctx, "myorg", "missing"); StatusCode(err) != http.StatusNotFound {
+		t.Errorf("CheckRepo(missing) = %v, want 404", err)
+	}
+}
+
+func TestListRunners(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddRepo("me/app", false)
+	// More than one page of 100.
+	for i := 1; i <= 150; i++ {
+		srv.AddRunner("myorg", fmt.Sprintf("ghr-runner-%d", i), "online", i%2 == 0, "linux", "x64")
+	}
+	srv.AddRunner("me/app", "ghr-runner-1", "offline", false)
+	c := newTestClient(t, srv, "t")
+
+	runners, err := c.ListOrgRunners(ctx, "myorg")
+	if err != nil {
+		t.Fatalf("ListOrgRunners() error = %v", err)
+	}
+	if len(runners) != 150 {
+		t.Fatalf("ListOrgRunners() returned %d runners, want 150", len(runners))
+	}
+	r := runners[1]
+	if r.Name != "ghr-runner-2" || r.Status != "online" || !r.Busy || !reflect.DeepEqual(r.Labels, []string{"linux", "x64"}) {
+		t.Errorf("ListOrgRunners()[1] = %+v", r)
+	}
+
+	runners, err = c.ListRepoRunners(ctx, "me", "app")
+	if err != nil {
+		t.Fatalf("ListRepoRunners() error = %v", err)
+	}
+	if len(runners) != 1 || runners[0].Status != "offline" {
+		t.Errorf("ListRepoRunners() = %+v, want one offline runner", runners)
+	}
+}
+
+func TestRegistrationAndRemoval(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddRepo("me/app", false)
+	idle := srv.AddRunner("myorg", "ghr-runner-1", "online", false)
+	busy := srv.AddRunner("myorg", "ghr-runner-2", "online", true)
+	repoRunner := srv.AddRunner("me/app", "ghr-runner-1", "online", false)
+	c := newTestClient(t, srv, "t")
+
+	if tok, err := c.CreateOrgRegistrationToken(ctx, "myorg"); err != nil || tok == "" {
+		t.Errorf("CreateOrgRegistrationToken() = %q, %v", tok, err)
+	}
+	if tok, err := c.CreateRepoRegistrationToken(ctx, "me", "app"); err != nil || tok == "" {
+		t.Errorf("CreateRepoRegistrationToken() = %q, %v", tok, err)
+	}
+	if srv.RegistrationTokens("myorg") != 1 || srv.RegistrationTokens("me/app") != 1 {
+		t.Error("expected one registration token for the org and one for the repo")
+	}
+
+	if err := c.RemoveOrgRunner(ctx, "myorg", idle); err != nil {
+		t.Errorf("RemoveOrgRunner() error = %v", err)
+	}
+	if err := c.RemoveOrgRunner(ctx, "myorg", busy); StatusCode(err) != http.StatusUnprocessableEntity {
+		t.Errorf("RemoveOrgRunner(busy) = %v, want 422", err)
+	}
+	if err := c.RemoveRepoRunner(ctx, "me", "app", repoRunner); err != nil {
+		t.Errorf("RemoveRepoRunner() error = %v", err)
+	}
+	if got := srv.Runners("myorg"); !reflect.DeepEqual(got, []string{"ghr-runner-2"}) {
+		t.Errorf("org runners = %v, want only the busy runner", got)
+	}
+	if got := srv.Runners("me/app"); len(got) != 0 {
+		t.Errorf("repo runners = %v, want none", got)
+	}
+}
+
+func TestEnterpriseRunners(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddEnterprise("acme")
+	srv.AddOrg("acme")
+	idle := srv.AddRunner("enterprise:acme", "ghr-runner-1", "online", false, "shared")
+	srv.AddRunner("acme", "org-runner", "online", false)
+	c := newTestClient(t, srv, "t")
+
+	runners, err := c.ListEnterpriseRunners(ctx, "acme")
+	if err != nil {
+		t.Fatalf("ListEnterpriseRunners() error = %v", err)
+	}
+	if len(runners) != 1 || runners[0].Name != "ghr-runner-1" || !reflect.DeepEqual(runners[0].Labels, []string{"shared"}) {
+		t.Errorf("ListEnterpriseRunners() = %+v, want only the enterprise runner", runners)
+	}
+	if _, err := c.ListEnterpriseRunners(ctx, "other"); StatusCode(err) != http.StatusNotFound {
+		t.Errorf("ListEnterpriseRunners(other) = %v, want 404", err)
+	}
+
+	if tok, err := c.CreateEnterpriseRegistrationToken(ctx, "acme"); err != nil || tok == "" {
+		t.Errorf("CreateEnterpriseRegistrationToken() = %q, %v", tok, err)
+	}
+	if n := srv.RegistrationTokens("enterprise:acme"); n != 1 {
+		t.Errorf("%d enterprise registration tokens, want 1", n)
+	}
+	if err := c.RemoveEnterpriseRunner(ctx, "acme", idle); err != nil {
+		t.Errorf("RemoveEnterpriseRunner() error = %v", err)
+	}
+	if got := srv.Runners("enterprise:acme"); len(got) != 0 {
+		t.Errorf("enterprise runners = %v, want none", got)
+	}
+	if got := srv.Runners("acme"); len(got) != 1 {
+		t.Errorf("org runners = %v, want the org runner kept", got)
+	}
+}
+
+func TestListRunnerGroups(t *testing.T) {
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddGroup("myorg", "Default")
+	srv.AddGroup("myorg", "gpu")
+
+	groups, err := newTestClient(t, srv, "t").ListRunnerGroups(context.Background(), "myorg")
+	if err != nil {
+		t.Fatalf("ListRunnerGroups() error = %v", err)
+	}
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	if want := []string{"Default", "gpu"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListRunnerGroups() names = %v, want %v", names, want)
+	}
+	if !groups[0].Default || groups[1].Default {
+		t.Errorf("ListRunnerGroups() = %+v, want only Default as the default group", groups)
+	}
+}
+
+func TestRunnerGroups(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddRepo("myorg/app", false)
+	srv.AddRepo("myorg/api", false)
+	srv.AddRepo("other/lib", false)
+	srv.AddGroup("myorg", "Default")
+	c := newTestClient(t, srv, "t")
+
+	g, err := c.CreateRunnerGroup(ctx, "myorg", "gpu", RunnerGroupOptions{
+		Visibility: "selected",
+		Repos:      []string{"app", "other/lib"},
+		Workflows:  []string{"myorg/app/.github/workflows/train.yml@refs/heads/main"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRunnerGroup() error = %v", err)
+	}
+	if g.Name != "gpu" || g.Visibility != "selected" || len(g.Workflows) != 1 {
+		t.Errorf("CreateRunnerGroup() = %+v", g)
+	}
+	repos, err := c.RunnerGroupRepos(ctx, "myorg", g.ID)
+	if err != nil {
+		t.Fatalf("RunnerGroupRepos() error = %v", err)
+	}
+	if want := []string{"myorg/app", "other/lib"}; !reflect.DeepEqual(repos, want) {
+		t.Errorf("RunnerGroupRepos() = %v, want %v", repos, want)
+	}
+
+	if _, err := c.CreateRunnerGroup(ctx, "myorg", "bad", RunnerGroupOptions{Visibility: "selected", Repos: []string{"missing"}}); err == nil {
+		t.Error("CreateRunnerGroup() with a missing repo succeeded, want error")
+	}
+
+	// Setting repositories switches an "all" group to "selected".
+	if err := c.SetRunnerGroupRepos(ctx, "myorg", "Default", []string{"myorg/api"}); err != nil {
+		t.Fatalf("SetRunnerGroupRepos() error = %v", err)
+	}
+	if got, repos := srv.Group("myorg", "Default"); got.GetVisibility() != "selected" || !reflect.DeepEqual(repos, []string{"myorg/api"}) {
+		t.Errorf("Default group = %s %v, want selected [myorg/api]", got.GetVisibility(), repos)
+	}
+	if err := c.SetRunnerGroupRepos(ctx, "myorg", "gpu", nil); err != nil {
+		t.Fatalf("SetRunnerGroupRepos(nil) error = %v", err)
+	}
+	if _, repos := srv.Group("myorg", "gpu"); len(repos) != 0 {
+		t.Errorf("gpu repos = %v after clearing, want none", repos)
+	}
+
+	deploy := []string{"myorg/api/.github/workflows/deploy.yml@refs/heads/main"}
+	if err := c.SetRunnerGroupWorkflows(ctx, "myorg", "gpu", deploy); err != nil {
+		t.Fatalf("SetRunnerGroupWorkflows() error = %v", err)
+	}
+	if g, err := c.FindRunnerGroup(ctx, "myorg", "gpu"); err != nil || !reflect.DeepEqual(g.Workflows, deploy) {
+		t.Errorf("gpu workflows = %v (err %v), want %v", g.Workflows, err, deploy)
+	}
+	if err := c.SetRunnerGroupWorkflows(ctx, "myorg", "gpu", nil); err != nil {
+		t.Fatalf("SetRunnerGroupWorkflows(nil) error = %v", err)
+	}
+	if got, _ := srv.Group("myorg", "gpu"); got.GetRestrictedToWorkflows() {
+		t.Errorf("gpu still restricted to %v after clearing workflows", got.SelectedWorkflows)
+	}
+
+	if err := c.DeleteRunnerGroup(ctx, "myorg", "gpu"); err != nil {
+		t.Fatalf("DeleteRunnerGroup() error = %v", err)
+	}
+	var notFound *GroupNotFoundError
+	if _, err := c.FindRunnerGroup(ctx, "myorg", "gpu"); !errors.As(err, &notFound) {
+		t.Fatalf("FindRunnerGroup() after delete error = %v, want *GroupNotFoundError", err)
+	}
+	if !reflect.DeepEqual(notFound.Available, []string{"Default"}) {
+		t.Errorf("GroupNotFoundError.Available = %v, want [Default]", notFound.Available)
+	}
+	if err := c.DeleteRunnerGroup(ctx, "myorg", "Default"); err == nil {
+		t.Error("DeleteRunnerGroup(Default) succeeded, want error")
+	}
+}
+
+func TestRunnerLabels(t *testing.T) {
+	ctx := context.Background()
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddRepo("me/app", false)
+	id := srv.AddRunner("myorg", "ghr-runner-1", "online", false, "self-hosted", "linux")
+	repoID := srv.AddRunner("me/app", "ghr-runner-1", "online", false, "self-hosted")
+	c := newTestClient(t, srv, "t")
+	org := RunnerOwner{Org: "myorg"}
+
+	labels, err := c.AddRunnerLabels(ctx, org, id, []string{"team-ml", "linux"})
+	if err != nil {
+		t.Fatalf("AddRunnerLabels() error = %v", err)
+	}
+	if want := []string{"linux", "team-ml"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("AddRunnerLabels() = %v, want %v", labels, want)
+	}
+	if labels, err = c.RemoveRunnerLabel(ctx, org, id, "linux"); err != nil || !reflect.DeepEqual(labels, []string{"team-ml"}) {
+		t.Errorf("RemoveRunnerLabel() = %v, %v, want [team-ml]", labels, err)
+	}
+	if _, err := c.RemoveRunnerLabel(ctx, org, id, "self-hosted"); StatusCode(err) != http.StatusNotFound {
+		t.Errorf("RemoveRunnerLabel(self-hosted) error = %v, want 404", err)
+	}
+	if labels, err = c.SetRunnerLabels(ctx, org, id, nil); err != nil || len(labels) != 0 {
+		t.Errorf("SetRunnerLabels(nil) = %v, %v, want no custom labels", labels, err)
+	}
+	if got := srv.Labels("myorg", id); !reflect.DeepEqual(got, []string{"self-hosted"}) {
+		t.Errorf("org runner labels = %v, want [self-hosted]", got)
+	}
+
+	if _, err := c.SetRunnerLabels(ctx, RunnerOwner{Repo: "me/app"}, repoID, []string{"gpu"}); err != nil {
+		t.Fatalf("SetRunnerLabels(repo) error = %v", err)
+	}
+	if got := srv.Labels("me/app", repoID); !reflect.DeepEqual(got, []string{"self-hosted", "gpu"}) {
+		t.Errorf("repo runner labels = %v, want [self-hosted gpu]", got)
+	}
+}
+
+func TestListOrgJobs(t *testing.T) {
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddRepo("myorg/app", false)
+	srv.AddRepo("myorg/old", true)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	at := func(d time.Duration) *gh.Timestamp { return &gh.Timestamp{Time: now.Add(d)} }
+	srv.AddRun("myorg/app", &gh.WorkflowRun{Name: gh.Ptr("CI"), CreatedAt: at(-time.Hour)},
+		&gh.WorkflowJob{
+			Name: gh.Ptr("build"), Status: gh.Ptr("completed"), Conclusion: gh.Ptr("success"),
+			RunnerName: gh.Ptr("ghr-runner-1"), StartedAt: at(-time.Hour), CompletedAt: at(-50 * time.Minute),
+		},
+		&gh.WorkflowJob{Name: gh.Ptr("test"), WorkflowName: gh.Ptr("Tests"), Status: gh.Ptr("queued")},
+	)
+	srv.AddRun("myorg/app", &gh.WorkflowRun{Name: gh.Ptr("CI"), CreatedAt: at(-48 * time.Hour)},
+		&gh.WorkflowJob{Name: gh.Ptr("too old")})
+	srv.AddRun("myorg/old", &gh.WorkflowRun{Name: gh.Ptr("CI"), CreatedAt: at(-time.Hour)},
+		&gh.WorkflowJob{Name: gh.Ptr("archived")})
+	srv.AddRepo("myorg/idle", false)
+	srv.SetPushedAt("myorg/idle", now.Add(-72*time.Hour))
+
+	jobs, err := newTestClient(t, srv, "t").ListOrgJobs(context.Background(), "myorg", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListOrgJobs() error = %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("ListOrgJobs() returned %d jobs, want 2: %+v", len(jobs), jobs)
+	}
+	build, test := jobs[0], jobs[1]
+	if build.Repo != "myorg/app" || build.Workflow != "CI" || build.RunnerName != "ghr-runner-1" || build.Duration() != 10*time.Minute {
+		t.Errorf("build job = %+v", build)
+	}
+	if test.Workflow != "Tests" || test.Duration() != 0 {
+		t.Errorf("test job = %+v", test)
+	}
+	// One request for the repos, one for app's runs and one for the jobs of
+	// its recent run. Archived and idle repos are not queried.
+	if reqs := srv.Requests(); len(reqs) != 3 {
+		t.Errorf("ListOrgJobs() made %d requests, want 3: %v", len(reqs), reqs)
+	}
+}
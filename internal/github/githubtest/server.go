@@ -0,0 +1,791 @@
+// Package githubtest provides a fake GitHub REST API server for tests. It
+// serves the endpoints ghr uses for self-hosted runners, runner groups,
+// registration tokens, workflow jobs and webhooks, backed by in-memory state,
+// so the internal/github client can be exercised without network access.
+package githubtest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v68/github"
+)
+
+// Server is a fake GitHub API. Runners, registration tokens and webhooks
+// belong to a target: an organization name, "owner/name" for a repository
+// or "enterprise:slug" for an enterprise, which has runners but no
+// webhooks. Point the client at URL, e.g. with github.NewClient. Paths are
+// also served under /api/v3, so URL can stand in for a GitHub Enterprise
+// Server instance.
+type Server struct {
+	*httptest.Server
+
+	// Token, if set, is the only token accepted; other requests get 401
+	// Bad credentials.
+	Token string
+	// Scopes is reported in the X-OAuth-Scopes header of GET /user. When
+	// nil the header is left out, as for fine-grained tokens.
+	Scopes []string
+
+	mu       sync.Mutex
+	nextID   int64
+	orgs     map[string]bool
+	ents     map[string]bool
+	repos    map[string]*gh.Repository
+	runners  map[string][]*gh.Runner
+	groups   map[string][]*gh.RunnerGroup
+	access   map[int64][]int64 // runner group ID -> selected repository IDs
+	runs     map[string][]*gh.WorkflowRun
+	jobs     map[int64][]*gh.WorkflowJob
+	hooks    map[string][]*gh.Hook
+	tokens   map[string]int
+	requests []string
+}
+
+// NewServer starts a fake GitHub API server that is closed when the test
+// ends.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		orgs:    make(map[string]bool),
+		ents:    make(map[string]bool),
+		repos:   make(map[string]*gh.Repository),
+		runners: make(map[string][]*gh.Runner),
+		groups:  make(map[string][]*gh.RunnerGroup),
+		access:  make(map[int64][]int64),
+		runs:    make(map[string][]*gh.WorkflowRun),
+		jobs:    make(map[int64][]*gh.WorkflowJob),
+		hooks:   make(map[string][]*gh.Hook),
+		tokens:  make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /user", s.getUser)
+	mux.HandleFunc("GET /orgs/{org}", s.getOrg)
+	mux.HandleFunc("GET /orgs/{org}/repos", s.listOrgRepos)
+	mux.HandleFunc("GET /repos/{owner}/{repo}", s.getRepo)
+	mux.HandleFunc("GET /orgs/{org}/actions/runner-groups", s.listGroups)
+	mux.HandleFunc("POST /orgs/{org}/actions/runner-groups", s.createGroup)
+	mux.HandleFunc("PATCH /orgs/{org}/actions/runner-groups/{group}", s.updateGroup)
+	mux.HandleFunc("DELETE /orgs/{org}/actions/runner-groups/{group}", s.deleteGroup)
+	mux.HandleFunc("GET /orgs/{org}/actions/runner-groups/{group}/repositories", s.listGroupRepos)
+	mux.HandleFunc("PUT /orgs/{org}/actions/runner-groups/{group}/repositories", s.setGroupRepos)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs", s.listRuns)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs/{run}/jobs", s.listJobs)
+	for _, prefix := range []string{"/orgs/{org}", "/repos/{owner}/{repo}"} {
+		mux.HandleFunc("GET "+prefix+"/actions/runners", s.listRunners)
+		mux.HandleFunc("POST "+prefix+"/actions/runners/registration-token", s.createToken)
+		mux.HandleFunc("DELETE "+prefix+"/actions/runners/{id}", s.removeRunner)
+		mux.HandleFunc("POST "+prefix+"/actions/runners/{id}/labels", s.addLabels)
+		mux.HandleFunc("PUT "+prefix+"/actions/runners/{id}/labels", s.setLabels)
+		mux.HandleFunc("DELETE "+prefix+"/actions/runners/{id}/labels/{name}", s.removeLabel)
+		mux.HandleFunc("GET "+prefix+"/hooks", s.listHooks)
+		mux.HandleFunc("POST "+prefix+"/hooks", s.createHook)
+		mux.HandleFunc("DELETE "+prefix+"/hooks/{id}", s.deleteHook)
+	}
+	mux.HandleFunc("GET /enterprises/{enterprise}/actions/runners", s.listRunners)
+	mux.HandleFunc("POST /enterprises/{enterprise}/actions/runners/registration-token", s.createToken)
+	mux.HandleFunc("DELETE /enterprises/{enterprise}/actions/runners/{id}", s.removeRunner)
+	mux.HandleFunc("POST /enterprises/{enterprise}/actions/runners/{id}/labels", s.addLabels)
+	mux.HandleFunc("PUT /enterprises/{enterprise}/actions/runners/{id}/labels", s.setLabels)
+	mux.HandleFunc("DELETE /enterprises/{enterprise}/actions/runners/{id}/labels/{name}", s.removeLabel)
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeError(w, http.StatusUnauthorized, "Bad credentials")
+			return
+		}
+		if rest, ok := strings.CutPrefix(r.URL.Path, "/api/v3/"); ok {
+			r.URL.Path = "/" + rest
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// AddOrg adds an organization.
+func (s *Server) AddOrg(org string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orgs[org] = true
+}
+
+// AddEnterprise adds an enterprise, whose target is "enterprise:" + slug.
+func (s *Server) AddEnterprise(slug string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ents[slug] = true
+}
+
+// AddRepo adds a repository, given as "owner/name", last pushed to now.
+// Archived repositories are listed with archived set.
+func (s *Server) AddRepo(fullName string, archived bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owner, name, _ := strings.Cut(fullName, "/")
+	s.repos[fullName] = &gh.Repository{
+		ID:       gh.Ptr(s.id()),
+		Name:     gh.Ptr(name),
+		FullName: gh.Ptr(fullName),
+		Owner:    &gh.User{Login: gh.Ptr(owner)},
+		Archived: gh.Ptr(archived),
+		PushedAt: &gh.Timestamp{Time: time.Now()},
+	}
+}
+
+// SetPushedAt sets the time a repository was last pushed to.
+func (s *Server) SetPushedAt(fullName string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos[fullName].PushedAt = &gh.Timestamp{Time: at}
+}
+
+// AddRunner registers a self-hosted runner with a target and returns its
+// ID. status is "online" or "offline".
+func (s *Server) AddRunner(target, name, status string, busy bool, labels ...string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := &gh.Runner{
+		ID:     gh.Ptr(s.id()),
+		Name:   gh.Ptr(name),
+		OS:     gh.Ptr("Linux"),
+		Status: gh.Ptr(status),
+		Busy:   gh.Ptr(busy),
+	}
+	for _, l := range labels {
+		r.Labels = append(r.Labels, s.label(l))
+	}
+	s.runners[target] = append(s.runners[target], r)
+	return r.GetID()
+}
+
+// Labels returns the labels of the runner with the given ID registered with
+// a target.
+func (s *Server) Labels(target string, id int64) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for _, r := range s.runners[target] {
+		if r.GetID() == id {
+			for _, l := range r.Labels {
+				names = append(names, l.GetName())
+			}
+		}
+	}
+	return names
+}
+
+// label returns a new runner label. "self-hosted" is read-only, as the
+// default labels are on GitHub; other labels are custom. s.mu must be held.
+func (s *Server) label(name string) *gh.RunnerLabels {
+	typ := "custom"
+	if name == "self-hosted" {
+		typ = "read-only"
+	}
+	return &gh.RunnerLabels{ID: gh.Ptr(s.id()), Name: gh.Ptr(name), Type: gh.Ptr(typ)}
+}
+
+// Runners returns the names of the runners registered with a target.
+func (s *Server) Runners(target string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for _, r := range s.runners[target] {
+		names = append(names, r.GetName())
+	}
+	return names
+}
+
+// AddGroup adds a runner group visible to all repositories to an
+// organization. A group named "Default" is the default group, which cannot
+// be deleted.
+func (s *Server) AddGroup(org, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups[org] = append(s.groups[org], &gh.RunnerGroup{
+		ID:         gh.Ptr(s.id()),
+		Name:       gh.Ptr(name),
+		Visibility: gh.Ptr("all"),
+		Default:    gh.Ptr(name == "Default"),
+	})
+}
+
+// Group returns the organization's runner group with the given name and the
+// full names of its selected repositories, or nil if there is no such group.
+func (s *Server) Group(org, name string) (*gh.RunnerGroup, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, g := range s.groups[org] {
+		if g.GetName() != name {
+			continue
+		}
+		var repos []string
+		for _, id := range s.access[g.GetID()] {
+			for _, r := range s.repos {
+				if r.GetID() == id {
+					repos = append(repos, r.GetFullName())
+				}
+			}
+		}
+		sort.Strings(repos)
+		return g, repos
+	}
+	return nil, nil
+}
+
+// AddRun adds a workflow run with its jobs to a repository, given as
+// "owner/name". Missing IDs are filled in, and each job's run ID is set.
+// Runs are filtered by CreatedAt when listed with a created filter.
+func (s *Server) AddRun(repo string, run *gh.WorkflowRun, jobs ...*gh.WorkflowJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if run.ID == nil {
+		run.ID = gh.Ptr(s.id())
+	}
+	for _, j := range jobs {
+		if j.ID == nil {
+			j.ID = gh.Ptr(s.id())
+		}
+		j.RunID = run.ID
+	}
+	s.runs[repo] = append(s.runs[repo], run)
+	s.jobs[run.GetID()] = append(s.jobs[run.GetID()], jobs...)
+}
+
+// RegistrationTokens returns how many registration tokens were issued for a
+// target.
+func (s *Server) RegistrationTokens(target string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[target]
+}
+
+// Hooks returns the webhooks created on a target.
+func (s *Server) Hooks(target string) []*gh.Hook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.hooks[target])
+}
+
+// Requests returns the requests served so far as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Deliver sends a webhook event to every active hook on the target that
+// subscribes to it, the way GitHub does: a JSON POST with the X-GitHub-Event
+// header and, when the hook has a secret, an X-Hub-Signature-256 header.
+func (s *Server) Deliver(target, event string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	for _, h := range s.Hooks(target) {
+		if !h.GetActive() || !(slices.Contains(h.Events, event) || slices.Contains(h.Events, "*")) {
+			continue
+		}
+		req, err := http.NewRequest(http.MethodPost, h.GetConfig().GetURL(), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-GitHub-Hook-ID", strconv.FormatInt(h.GetID(), 10))
+		if secret := h.GetConfig().GetSecret(); secret != "" {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("delivering %s to hook %d: %w", event, h.GetID(), err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("delivering %s to hook %d: %s", event, h.GetID(), resp.Status)
+		}
+	}
+	return nil
+}
+
+// id returns a new object ID. s.mu must be held.
+func (s *Server) id() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// target returns the runner target of a request and whether it exists.
+// s.mu must be held.
+func (s *Server) target(r *http.Request) (string, bool) {
+	if org := r.PathValue("org"); org != "" {
+		return org, s.orgs[org]
+	}
+	if ent := r.PathValue("enterprise"); ent != "" {
+		return "enterprise:" + ent, s.ents[ent]
+	}
+	repo := r.PathValue("owner") + "/" + r.PathValue("repo")
+	return repo, s.repos[repo] != nil
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	if s.Scopes != nil {
+		w.Header().Set("X-OAuth-Scopes", strings.Join(s.Scopes, ", "))
+	}
+	writeJSON(w, http.StatusOK, &gh.User{Login: gh.Ptr("ghr-test")})
+}
+
+func (s *Server) getOrg(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := r.PathValue("org")
+	if !s.orgs[org] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, &gh.Organization{Login: gh.Ptr(org)})
+}
+
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo, ok := s.repos[r.PathValue("owner")+"/"+r.PathValue("repo")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) listOrgRepos(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := r.PathValue("org")
+	if !s.orgs[org] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var repos []*gh.Repository
+	for name, repo := range s.repos {
+		if strings.HasPrefix(name, org+"/") {
+			repos = append(repos, repo)
+		}
+	}
+	slices.SortFunc(repos, func(a, b *gh.Repository) int { return strings.Compare(a.GetFullName(), b.GetFullName()) })
+	if r.URL.Query().Get("sort") == "pushed" {
+		slices.SortStableFunc(repos, func(a, b *gh.Repository) int {
+			return b.GetPushedAt().Time.Compare(a.GetPushedAt().Time)
+		})
+	}
+	writeJSON(w, http.StatusOK, paginate(w, r, repos))
+}
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := r.PathValue("org")
+	if !s.orgs[org] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	groups := s.groups[org]
+	writeJSON(w, http.StatusOK, &gh.RunnerGroups{TotalCount: len(groups), RunnerGroups: paginate(w, r, groups)})
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	var req gh.CreateRunnerGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := r.PathValue("org")
+	if !s.orgs[org] {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if req.GetName() == "" || slices.ContainsFunc(s.groups[org], func(g *gh.RunnerGroup) bool { return g.GetName() == req.GetName() }) {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	visibility := req.GetVisibility()
+	if visibility == "" {
+		visibility = "all"
+	}
+	g := &gh.RunnerGroup{
+		ID:                       gh.Ptr(s.id()),
+		Name:                     req.Name,
+		Visibility:               gh.Ptr(visibility),
+		Default:                  gh.Ptr(false),
+		AllowsPublicRepositories: gh.Ptr(req.GetAllowsPublicRepositories()),
+		RestrictedToWorkflows:    gh.Ptr(req.GetRestrictedToWorkflows()),
+		SelectedWorkflows:        req.SelectedWorkflows,
+	}
+	s.groups[org] = append(s.groups[org], g)
+	s.access[g.GetID()] = req.SelectedRepositoryIDs
+	writeJSON(w, http.StatusCreated, g)
+}
+
+// group returns the runner group of a request. s.mu must be held.
+func (s *Server) group(r *http.Request) (*gh.RunnerGroup, bool) {
+	id, _ := strconv.ParseInt(r.PathValue("group"), 10, 64)
+	for _, g := range s.groups[r.PathValue("org")] {
+		if g.GetID() == id {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	var req gh.UpdateRunnerGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.group(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if req.Visibility != nil {
+		g.Visibility = req.Visibility
+	}
+	if req.RestrictedToWorkflows != nil {
+		g.RestrictedToWorkflows = req.RestrictedToWorkflows
+		g.SelectedWorkflows = req.SelectedWorkflows
+	}
+	if req.AllowsPublicRepositories != nil {
+		g.AllowsPublicRepositories = req.AllowsPublicRepositories
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.group(r)
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, "Not Found")
+	case g.GetDefault():
+		writeError(w, http.StatusUnprocessableEntity, "The default runner group cannot be deleted")
+	default:
+		org := r.PathValue("org")
+		s.groups[org] = slices.DeleteFunc(s.groups[org], func(x *gh.RunnerGroup) bool { return x == g })
+		delete(s.access, g.GetID())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) listGroupRepos(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.group(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var repos []*gh.Repository
+	for _, id := range s.access[g.GetID()] {
+		for _, repo := range s.repos {
+			if repo.GetID() == id {
+				repos = append(repos, repo)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, &gh.ListRepositories{TotalCount: gh.Ptr(len(repos)), Repositories: paginate(w, r, repos)})
+}
+
+func (s *Server) setGroupRepos(w http.ResponseWriter, r *http.Request) {
+	var req gh.SetRepoAccessRunnerGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SelectedRepositoryIDs == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request: selected_repository_ids is required")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.group(r)
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, "Not Found")
+	case g.GetVisibility() != "selected":
+		writeError(w, http.StatusConflict, "Runner group visibility must be set to 'selected'")
+	default:
+		s.access[g.GetID()] = req.SelectedRepositoryIDs
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) listRunners(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.target(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	runners := s.runners[target]
+	writeJSON(w, http.StatusOK, &gh.Runners{TotalCount: len(runners), Runners: paginate(w, r, runners)})
+}
+
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.target(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	s.tokens[target]++
+	writeJSON(w, http.StatusCreated, &gh.RegistrationToken{
+		Token:     gh.Ptr(fmt.Sprintf("REGTOKEN%d", s.id())),
+		ExpiresAt: &gh.Timestamp{Time: time.Now().Add(time.Hour)},
+	})
+}
+
+func (s *Server) removeRunner(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.target(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	runners := s.runners[target]
+	i := slices.IndexFunc(runners, func(rn *gh.Runner) bool { return rn.GetID() == id })
+	switch {
+	case i < 0:
+		writeError(w, http.StatusNotFound, "Not Found")
+	case runners[i].GetBusy():
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Bad request - Runner %q is still running a job", runners[i].GetName()))
+	default:
+		s.runners[target] = slices.Delete(runners, i, i+1)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// runner returns the runner of a label request. s.mu must be held.
+func (s *Server) runner(r *http.Request) (*gh.Runner, bool) {
+	target, ok := s.target(r)
+	if !ok {
+		return nil, false
+	}
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	for _, rn := range s.runners[target] {
+		if rn.GetID() == id {
+			return rn, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) addLabels(w http.ResponseWriter, r *http.Request) {
+	s.changeLabels(w, r, func(rn *gh.Runner, labels []string) {
+		for _, l := range labels {
+			if !slices.ContainsFunc(rn.Labels, func(x *gh.RunnerLabels) bool { return x.GetName() == l }) {
+				rn.Labels = append(rn.Labels, s.label(l))
+			}
+		}
+	})
+}
+
+func (s *Server) setLabels(w http.ResponseWriter, r *http.Request) {
+	s.changeLabels(w, r, func(rn *gh.Runner, labels []string) {
+		rn.Labels = slices.DeleteFunc(rn.Labels, func(x *gh.RunnerLabels) bool { return x.GetType() == "custom" })
+		for _, l := range labels {
+			rn.Labels = append(rn.Labels, s.label(l))
+		}
+	})
+}
+
+// changeLabels decodes a label request body, applies it with fn and responds
+// with the runner's labels.
+func (s *Server) changeLabels(w http.ResponseWriter, r *http.Request, fn func(*gh.Runner, []string)) {
+	var req struct {
+		Labels []string `json:"labels"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Labels == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request: labels is required")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rn, ok := s.runner(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	fn(rn, req.Labels)
+	writeLabels(w, rn)
+}
+
+func (s *Server) removeLabel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rn, ok := s.runner(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	name := r.PathValue("name")
+	i := slices.IndexFunc(rn.Labels, func(x *gh.RunnerLabels) bool { return x.GetName() == name && x.GetType() == "custom" })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	rn.Labels = slices.Delete(rn.Labels, i, i+1)
+	writeLabels(w, rn)
+}
+
+func writeLabels(w http.ResponseWriter, rn *gh.Runner) {
+	writeJSON(w, http.StatusOK, map[string]any{"total_count": len(rn.Labels), "labels": rn.Labels})
+}
+
+func (s *Server) listRuns(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := r.PathValue("owner") + "/" + r.PathValue("repo")
+	if s.repos[repo] == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	var since time.Time
+	if created, ok := strings.CutPrefix(r.URL.Query().Get("created"), ">="); ok {
+		since, _ = time.Parse(time.RFC3339, created)
+	}
+	var runs []*gh.WorkflowRun
+	for _, run := range s.runs[repo] {
+		if !run.GetCreatedAt().Time.Before(since) {
+			runs = append(runs, run)
+		}
+	}
+	writeJSON(w, http.StatusOK, &gh.WorkflowRuns{TotalCount: gh.Ptr(len(runs)), WorkflowRuns: paginate(w, r, runs)})
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, _ := strconv.ParseInt(r.PathValue("run"), 10, 64)
+	jobs, ok := s.jobs[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, &gh.Jobs{TotalCount: gh.Ptr(len(jobs)), Jobs: paginate(w, r, jobs)})
+}
+
+func (s *Server) listHooks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.target(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(w, r, s.hooks[target]))
+}
+
+func (s *Server) createHook(w http.ResponseWriter, r *http.Request) {
+	var hook gh.Hook
+	if err := json.NewDecoder(r.Body).Decode(&hook); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if hook.GetConfig().GetURL() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.target(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	hook.ID = gh.Ptr(s.id())
+	if hook.Active == nil {
+		hook.Active = gh.Ptr(true)
+	}
+	if len(hook.Events) == 0 {
+		hook.Events = []string{"push"}
+	}
+	s.hooks[target] = append(s.hooks[target], &hook)
+	writeJSON(w, http.StatusCreated, &hook)
+}
+
+func (s *Server) deleteHook(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.target(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	hooks := s.hooks[target]
+	i := slices.IndexFunc(hooks, func(h *gh.Hook) bool { return h.GetID() == id })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	s.hooks[target] = slices.Delete(hooks, i, i+1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// paginate returns the page of items selected by the page and per_page
+// query parameters (default 30 per page, like GitHub) and sets the Link
+// header when there is a next page.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) []T {
+	q := r.URL.Query()
+	perPage, err := strconv.Atoi(q.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	if end < len(items) {
+		q.Set("page", strconv.Itoa(page+1))
+		next := "http://" + r.Host + r.URL.Path + "?" + q.Encode()
+		w.Header().Set("Link", `<`+next+`>; rel="next"`)
+	}
+	return append([]T{}, items[start:end]...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
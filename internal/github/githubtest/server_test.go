@@ -0,0 +1,93 @@
+package githubtest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	gh "github.com/google/go-github/v68/github"
+)
+
+func TestWebhooks(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t)
+	srv.AddOrg("myorg")
+	base, _ := url.Parse(srv.URL + "/")
+	client := gh.NewClient(nil)
+	client.BaseURL = base
+
+	type delivery struct {
+		event, signature, body string
+	}
+	got := make(chan delivery, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- delivery{r.Header.Get("X-GitHub-Event"), r.Header.Get("X-Hub-Signature-256"), string(body)}
+	}))
+	defer receiver.Close()
+
+	hook, _, err := client.Organizations.CreateHook(ctx, "myorg", &gh.Hook{
+		Config: &gh.HookConfig{URL: gh.Ptr(receiver.URL), ContentType: gh.Ptr("json"), Secret: gh.Ptr("s3cret")},
+		Events: []string{"workflow_job"},
+	})
+	if err != nil {
+		t.Fatalf("CreateHook() error = %v", err)
+	}
+	if hooks, _, err := client.Organizations.ListHooks(ctx, "myorg", nil); err != nil || len(hooks) != 1 {
+		t.Fatalf("ListHooks() = %v, %v, want one hook", hooks, err)
+	}
+
+	// Events the hook does not subscribe to are not sent.
+	if err := srv.Deliver("myorg", "push", map[string]string{}); err != nil {
+		t.Fatalf("Deliver(push) error = %v", err)
+	}
+	if err := srv.Deliver("myorg", "workflow_job", map[string]string{"action": "queued"}); err != nil {
+		t.Fatalf("Deliver(workflow_job) error = %v", err)
+	}
+	d := <-got
+	if d.event != "workflow_job" || d.body != `{"action":"queued"}` {
+		t.Errorf("delivered %q with %s, want workflow_job", d.event, d.body)
+	}
+	if err := gh.ValidateSignature(d.signature, []byte(d.body), []byte("s3cret")); err != nil {
+		t.Errorf("signature does not validate: %v", err)
+	}
+
+	if _, err := client.Organizations.DeleteHook(ctx, "myorg", hook.GetID()); err != nil {
+		t.Fatalf("DeleteHook() error = %v", err)
+	}
+	if n := len(srv.Hooks("myorg")); n != 0 {
+		t.Errorf("%d hooks left after DeleteHook(), want 0", n)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	srv := NewServer(t)
+	srv.AddOrg("myorg")
+	for i := 0; i < 5; i++ {
+		srv.AddRunner("myorg", "r", "online", false)
+	}
+	base, _ := url.Parse(srv.URL + "/")
+	client := gh.NewClient(nil)
+	client.BaseURL = base
+
+	opts := &gh.ListRunnersOptions{ListOptions: gh.ListOptions{PerPage: 2}}
+	var pages, total int
+	for {
+		runners, resp, err := client.Actions.ListOrganizationRunners(context.Background(), "myorg", opts)
+		if err != nil {
+			t.Fatalf("ListOrganizationRunners() error = %v", err)
+		}
+		pages++
+		total += len(runners.Runners)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	if pages != 3 || total != 5 {
+		t.Errorf("got %d runners over %d pages, want 5 over 3", total, pages)
+	}
+}
@@ -0,0 +1,213 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gh "github.com/google/go-github/v68/github"
+)
+
+// RunnerGroup holds simplified org runner group info.
+type RunnerGroup struct {
+	ID         int64
+	Name       string
+	Visibility string // all, selected or private
+	Default    bool
+	// AllowsPublicRepos lets public repositories use the group's runners.
+	AllowsPublicRepos bool
+	// Workflows lists the workflows allowed to use the group's runners, as
+	// OWNER/REPO/PATH@REF; empty means any workflow.
+	Workflows []string
+}
+
+// RunnerGroupOptions are the settings of a new runner group.
+type RunnerGroupOptions struct {
+	Visibility        string
+	Repos             []string // for visibility "selected"; see RepoIDs
+	Workflows         []string
+	AllowsPublicRepos bool
+}
+
+// GroupNotFoundError reports a runner group name that does not exist in an
+// organization.
+type GroupNotFoundError struct {
+	Org       string
+	Name      string
+	Available []string
+}
+
+func (e *GroupNotFoundError) Error() string {
+	return fmt.Sprintf("runner group %q not found in org %s (available: %s)", e.Name, e.Org, strings.Join(e.Available, ", "))
+}
+
+// ListRunnerGroups returns the organization's runner groups.
+func (c *Client) ListRunnerGroups(ctx context.Context, org string) ([]RunnerGroup, error) {
+	var all []RunnerGroup
+	opts := &gh.ListOrgRunnerGroupOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+	for {
+		groups, resp, err := c.gh.Actions.ListOrganizationRunnerGroups(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing runner groups: %w", err)
+		}
+		for _, g := range groups.RunnerGroups {
+			all = append(all, toRunnerGroup(g))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// FindRunnerGroup returns the organization's runner group with the given
+// name, or a *GroupNotFoundError.
+func (c *Client) FindRunnerGroup(ctx context.Context, org, name string) (RunnerGroup, error) {
+	groups, err := c.ListRunnerGroups(ctx, org)
+	if err != nil {
+		return RunnerGroup{}, err
+	}
+	var names []string
+	for _, g := range groups {
+		if g.Name == name {
+			return g, nil
+		}
+		names = append(names, g.Name)
+	}
+	return RunnerGroup{}, &GroupNotFoundError{Org: org, Name: name, Available: names}
+}
+
+// RunnerGroupRepos returns the repositories, as "owner/name", that may use
+// a runner group with "selected" visibility.
+func (c *Client) RunnerGroupRepos(ctx context.Context, org string, groupID int64) ([]string, error) {
+	var names []string
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		repos, resp, err := c.gh.Actions.ListRepositoryAccessRunnerGroup(ctx, org, groupID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing runner group repositories: %w", err)
+		}
+		for _, r := range repos.Repositories {
+			names = append(names, r.GetFullName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return names, nil
+}
+
+// CreateRunnerGroup creates a runner group in the organization. Giving
+// workflows restricts the group to them.
+func (c *Client) CreateRunnerGroup(ctx context.Context, org, name string, opts RunnerGroupOptions) (RunnerGroup, error) {
+	ids, err := c.RepoIDs(ctx, org, opts.Repos)
+	if err != nil {
+		return RunnerGroup{}, err
+	}
+	req := gh.CreateRunnerGroupRequest{
+		Name:                     gh.Ptr(name),
+		Visibility:               gh.Ptr(opts.Visibility),
+		SelectedRepositoryIDs:    ids,
+		AllowsPublicRepositories: gh.Ptr(opts.AllowsPublicRepos),
+	}
+	if len(opts.Workflows) > 0 {
+		req.RestrictedToWorkflows = gh.Ptr(true)
+		req.SelectedWorkflows = opts.Workflows
+	}
+	g, _, err := c.gh.Actions.CreateOrganizationRunnerGroup(ctx, org, req)
+	if err != nil {
+		return RunnerGroup{}, fmt.Errorf("creating runner group %s: %w", name, err)
+	}
+	return toRunnerGroup(g), nil
+}
+
+// DeleteRunnerGroup deletes the organization's runner group with the given
+// name. Its runners move to the default group.
+func (c *Client) DeleteRunnerGroup(ctx context.Context, org, name string) error {
+	g, err := c.FindRunnerGroup(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	if _, err := c.gh.Actions.DeleteOrganizationRunnerGroup(ctx, org, g.ID); err != nil {
+		return fmt.Errorf("deleting runner group %s: %w", name, err)
+	}
+	return nil
+}
+
+// SetRunnerGroupRepos replaces the repositories that may use the named
+// runner group, switching its visibility to "selected" first if needed.
+func (c *Client) SetRunnerGroupRepos(ctx context.Context, org, name string, repos []string) error {
+	g, err := c.FindRunnerGroup(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	ids, err := c.RepoIDs(ctx, org, repos)
+	if err != nil {
+		return err
+	}
+	if g.Visibility != "selected" {
+		req := gh.UpdateRunnerGroupRequest{Visibility: gh.Ptr("selected")}
+		if _, _, err := c.gh.Actions.UpdateOrganizationRunnerGroup(ctx, org, g.ID, req); err != nil {
+			return fmt.Errorf("updating runner group %s: %w", name, err)
+		}
+	}
+	req := gh.SetRepoAccessRunnerGroupRequest{SelectedRepositoryIDs: ids}
+	if ids == nil {
+		req.SelectedRepositoryIDs = []int64{}
+	}
+	if _, err := c.gh.Actions.SetRepositoryAccessRunnerGroup(ctx, org, g.ID, req); err != nil {
+		return fmt.Errorf("setting runner group %s repositories: %w", name, err)
+	}
+	return nil
+}
+
+// SetRunnerGroupWorkflows replaces the workflows allowed to use the named
+// runner group. With no workflows, any workflow may use it.
+func (c *Client) SetRunnerGroupWorkflows(ctx context.Context, org, name string, workflows []string) error {
+	g, err := c.FindRunnerGroup(ctx, org, name)
+	if err != nil {
+		return err
+	}
+	req := gh.UpdateRunnerGroupRequest{
+		RestrictedToWorkflows: gh.Ptr(len(workflows) > 0),
+		SelectedWorkflows:     workflows,
+	}
+	if _, _, err := c.gh.Actions.UpdateOrganizationRunnerGroup(ctx, org, g.ID, req); err != nil {
+		return fmt.Errorf("setting runner group %s workflows: %w", name, err)
+	}
+	return nil
+}
+
+// RepoIDs looks up the IDs of repos, given as "name" for a repository in
+// org or as "owner/name".
+func (c *Client) RepoIDs(ctx context.Context, org string, repos []string) ([]int64, error) {
+	var ids []int64
+	for _, r := range repos {
+		owner, name, ok := strings.Cut(r, "/")
+		if !ok {
+			owner, name = org, r
+		}
+		repo, _, err := c.gh.Repositories.Get(ctx, owner, name)
+		if err != nil {
+			return nil, fmt.Errorf("getting repo %s/%s: %w", owner, name, err)
+		}
+		ids = append(ids, repo.GetID())
+	}
+	return ids, nil
+}
+
+func toRunnerGroup(g *gh.RunnerGroup) RunnerGroup {
+	rg := RunnerGroup{
+		ID:                g.GetID(),
+		Name:              g.GetName(),
+		Visibility:        g.GetVisibility(),
+		Default:           g.GetDefault(),
+		AllowsPublicRepos: g.GetAllowsPublicRepositories(),
+	}
+	if g.GetRestrictedToWorkflows() {
+		rg.Workflows = g.SelectedWorkflows
+	}
+	return rg
+}
@@ -0,0 +1,144 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gh "github.com/google/go-github/v68/github"
+)
+
+// WorkflowJob holds simplified info about a workflow job and the runner that
+// executed it.
+type WorkflowJob struct {
+	ID          int64
+	RunID       int64
+	Repo        string // owner/name
+	Workflow    string
+	Name        string
+	Status      string // queued, in_progress, completed
+	Conclusion  string // success, failure, cancelled, ... (empty until completed)
+	RunnerName  string
+	CreatedAt   time.Time
+	StartedAt   time.Time
+	CompletedAt time.Time
+	HTMLURL     string
+}
+
+// Duration returns how long the job ran, or zero if it has not completed.
+func (j WorkflowJob) Duration() time.Duration {
+	if j.StartedAt.IsZero() || j.CompletedAt.IsZero() {
+		return 0
+	}
+	return j.CompletedAt.Sub(j.StartedAt)
+}
+
+// ListRepoJobs lists the jobs of all workflow runs created in a repository
+// since the given time.
+func (c *Client) ListRepoJobs(ctx context.Context, owner, repo string, since time.Time) ([]WorkflowJob, error) {
+	var all []WorkflowJob
+	opts := &gh.ListWorkflowRunsOptions{
+		Created:     ">=" + since.UTC().Format(time.RFC3339),
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+
+	for {
+		runs, resp, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing workflow runs for %s/%s: %w", owner, repo, err)
+		}
+		for _, run := range runs.WorkflowRuns {
+			// Runs are listed newest first.
+			if run.GetCreatedAt().Time.Before(since) {
+				return all, nil
+			}
+			jobs, err := c.listRunJobs(ctx, owner, repo, run)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, jobs...)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// ListOrgJobs lists the jobs of all workflow runs created since the given
+// time across the non-archived repositories in an organization. To stay
+// within the API rate limit, only repositories pushed to since then are
+// searched, so runs started only by a schedule or another repository's
+// workflow in an otherwise idle repository are missed.
+func (c *Client) ListOrgJobs(ctx context.Context, org string, since time.Time) ([]WorkflowJob, error) {
+	var all []WorkflowJob
+	opts := &gh.RepositoryListByOrgOptions{
+		Sort:        "pushed",
+		Direction:   "desc",
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+
+	for {
+		repos, resp, err := c.gh.Repositories.ListByOrg(ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing org repos: %w", err)
+		}
+		for _, r := range repos {
+			// Repos are listed by last push, newest first.
+			if r.GetPushedAt().Time.Before(since) {
+				return all, nil
+			}
+			if r.GetArchived() {
+				continue
+			}
+			jobs, err := c.ListRepoJobs(ctx, org, r.GetName(), since)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, jobs...)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+func (c *Client) listRunJobs(ctx context.Context, owner, repo string, run *gh.WorkflowRun) ([]WorkflowJob, error) {
+	var all []WorkflowJob
+	opts := &gh.ListWorkflowJobsOptions{Filter: "all", ListOptions: gh.ListOptions{PerPage: 100}}
+
+	for {
+		jobs, resp, err := c.gh.Actions.ListWorkflowJobs(ctx, owner, repo, run.GetID(), opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing jobs for run %d: %w", run.GetID(), err)
+		}
+		for _, j := range jobs.Jobs {
+			workflow := j.GetWorkflowName()
+			if workflow == "" {
+				workflow = run.GetName()
+			}
+			all = append(all, WorkflowJob{
+				ID:          j.GetID(),
+				RunID:       j.GetRunID(),
+				Repo:        owner + "/" + repo,
+				Workflow:    workflow,
+				Name:        j.GetName(),
+				Status:      j.GetStatus(),
+				Conclusion:  j.GetConclusion(),
+				RunnerName:  j.GetRunnerName(),
+				CreatedAt:   j.GetCreatedAt().Time,
+				StartedAt:   j.GetStartedAt().Time,
+				CompletedAt: j.GetCompletedAt().Time,
+				HTMLURL:     j.GetHTMLURL(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
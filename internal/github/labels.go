@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// RunnerOwner is the org, repository or enterprise a runner is registered
+// with. Exactly one field is set.
+type RunnerOwner struct {
+	Org        string
+	Repo       string // "owner/name"
+	Enterprise string
+}
+
+func (o RunnerOwner) path() string {
+	switch {
+	case o.Org != "":
+		return "orgs/" + o.Org
+	case o.Enterprise != "":
+		return "enterprises/" + o.Enterprise
+	}
+	return "repos/" + o.Repo
+}
+
+// runnerLabels is the response of the runner label endpoints, which go-github
+// does not wrap.
+type runnerLabels struct {
+	Labels []struct {
+		Name string `json:"name"`
+		Type string `json:"type"` // read-only or custom
+	} `json:"labels"`
+}
+
+// AddRunnerLabels adds custom labels to a registered runner and returns its
+// custom labels afterwards.
+func (c *Client) AddRunnerLabels(ctx context.Context, owner RunnerOwner, id int64, labels []string) ([]string, error) {
+	return c.runnerLabels(ctx, http.MethodPost, owner, id, "", labels)
+}
+
+// SetRunnerLabels replaces the custom labels of a registered runner and
+// returns them.
+func (c *Client) SetRunnerLabels(ctx context.Context, owner RunnerOwner, id int64, labels []string) ([]string, error) {
+	if labels == nil {
+		labels = []string{}
+	}
+	return c.runnerLabels(ctx, http.MethodPut, owner, id, "", labels)
+}
+
+// RemoveRunnerLabel removes a custom label from a registered runner and
+// returns its custom labels afterwards.
+func (c *Client) RemoveRunnerLabel(ctx context.Context, owner RunnerOwner, id int64, label string) ([]string, error) {
+	return c.runnerLabels(ctx, http.MethodDelete, owner, id, label, nil)
+}
+
+// runnerLabels calls a runner label endpoint: the one for a single label if
+// label is set, otherwise the one for all labels with labels as the body.
+func (c *Client) runnerLabels(ctx context.Context, method string, owner RunnerOwner, id int64, label string, labels []string) ([]string, error) {
+	path := fmt.Sprintf("%s/actions/runners/%d/labels", owner.path(), id)
+	if label != "" {
+		path += "/" + url.PathEscape(label)
+	}
+	var body any
+	if labels != nil {
+		body = map[string][]string{"labels": labels}
+	}
+	req, err := c.gh.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	var resp runnerLabels
+	if _, err := c.gh.Do(ctx, req, &resp); err != nil {
+		return nil, fmt.Errorf("changing labels of runner %d: %w", id, err)
+	}
+	custom := []string{}
+	for _, l := range resp.Labels {
+		if l.Type == "custom" {
+			custom = append(custom, l.Name)
+		}
+	}
+	return custom, nil
+}
@@ -14,6 +14,9 @@ type RunnerStatus struct {
 	Status string // online, offline
 	Busy   bool
 	Labels []string
+	// CustomLabels are the labels in Labels that can be changed, as opposed
+	// to the read-only ones GitHub gives every self-hosted runner.
+	CustomLabels []string
 }
 
 // ListOrgRunners lists all self-hosted runners for an organization.
@@ -27,16 +30,20 @@ func (c *Client) ListOrgRunners(ctx context.Context, org string) ([]RunnerStatus
 			return nil, fmt.Errorf("listing org runners: %w", err)
 		}
 		for _, r := range runners.Runners {
-			var labels []string
+			var labels, custom []string
 			for _, l := range r.Labels {
 				labels = append(labels, l.GetName())
+				if l.GetType() == "custom" {
+					custom = append(custom, l.GetName())
+				}
 			}
 			all = append(all, RunnerStatus{
-				ID:     r.GetID(),
-				Name:   r.GetName(),
-				Status: r.GetStatus(),
-				Busy:   r.GetBusy(),
-				Labels: labels,
+				ID:           r.GetID(),
+				Name:         r.GetName(),
+				Status:       r.GetStatus(),
+				Busy:         r.GetBusy(),
+				Labels:       labels,
+				CustomLabels: custom,
 			})
 		}
 		if resp.NextPage == 0 {
@@ -58,16 +65,20 @@ func (c *Client) ListRepoRunners(ctx context.Context, owner, repo string) ([]Run
 			return nil, fmt.Errorf("listing repo runners: %w", err)
 		}
 		for _, r := range runners.Runners {
-			var labels []string
+			var labels, custom []string
 			for _, l := range r.Labels {
 				labels = append(labels, l.GetName())
+				if l.GetType() == "custom" {
+					custom = append(custom, l.GetName())
+				}
 			}
 			all = append(all, RunnerStatus{
-				ID:     r.GetID(),
-				Name:   r.GetName(),
-				Status: r.GetStatus(),
-				Busy:   r.GetBusy(),
-				Labels: labels,
+				ID:           r.GetID(),
+				Name:         r.GetName(),
+				Status:       r.GetStatus(),
+				Busy:         r.GetBusy(),
+				Labels:       labels,
+				CustomLabels: custom,
 			})
 		}
 		if resp.NextPage == 0 {
@@ -77,3 +88,95 @@ func (c *Client) ListRepoRunners(ctx context.Context, owner, repo string) ([]Run
 	}
 	return all, nil
 }
+
+// ListEnterpriseRunners lists all self-hosted runners for an enterprise.
+func (c *Client) ListEnterpriseRunners(ctx context.Context, enterprise string) ([]RunnerStatus, error) {
+	var all []RunnerStatus
+	opts := &gh.ListOptions{PerPage: 100}
+
+	for {
+		runners, resp, err := c.gh.Enterprise.ListRunners(ctx, enterprise, &gh.ListRunnersOptions{ListOptions: *opts})
+		if err != nil {
+			return nil, fmt.Errorf("listing enterprise runners: %w", err)
+		}
+		for _, r := range runners.Runners {
+			var labels, custom []string
+			for _, l := range r.Labels {
+				labels = append(labels, l.GetName())
+				if l.GetType() == "custom" {
+					custom = append(custom, l.GetName())
+				}
+			}
+			all = append(all, RunnerStatus{
+				ID:           r.GetID(),
+				Name:         r.GetName(),
+				Status:       r.GetStatus(),
+				Busy:         r.GetBusy(),
+				Labels:       labels,
+				CustomLabels: custom,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// CreateOrgRegistrationToken returns a token that registers a runner with
+// an organization. It expires after an hour.
+func (c *Client) CreateOrgRegistrationToken(ctx context.Context, org string) (string, error) {
+	tok, _, err := c.gh.Actions.CreateOrganizationRegistrationToken(ctx, org)
+	if err != nil {
+		return "", fmt.Errorf("creating org registration token: %w", err)
+	}
+	return tok.GetToken(), nil
+}
+
+// CreateRepoRegistrationToken returns a token that registers a runner with
+// a repository. It expires after an hour.
+func (c *Client) CreateRepoRegistrationToken(ctx context.Context, owner, repo string) (string, error) {
+	tok, _, err := c.gh.Actions.CreateRegistrationToken(ctx, owner, repo)
+	if err != nil {
+		return "", fmt.Errorf("creating repo registration token: %w", err)
+	}
+	return tok.GetToken(), nil
+}
+
+// CreateEnterpriseRegistrationToken returns a token that registers a
+// runner with an enterprise. It expires after an hour.
+func (c *Client) CreateEnterpriseRegistrationToken(ctx context.Context, enterprise string) (string, error) {
+	tok, _, err := c.gh.Enterprise.CreateRegistrationToken(ctx, enterprise)
+	if err != nil {
+		return "", fmt.Errorf("creating enterprise registration token: %w", err)
+	}
+	return tok.GetToken(), nil
+}
+
+// RemoveOrgRunner removes a self-hosted runner from an organization. GitHub
+// refuses while the runner is running a job.
+func (c *Client) RemoveOrgRunner(ctx context.Context, org string, id int64) error {
+	if _, err := c.gh.Actions.RemoveOrganizationRunner(ctx, org, id); err != nil {
+		return fmt.Errorf("removing org runner %d: %w", id, err)
+	}
+	return nil
+}
+
+// RemoveRepoRunner removes a self-hosted runner from a repository. GitHub
+// refuses while the runner is running a job.
+func (c *Client) RemoveRepoRunner(ctx context.Context, owner, repo string, id int64) error {
+	if _, err := c.gh.Actions.RemoveRunner(ctx, owner, repo, id); err != nil {
+		return fmt.Errorf("removing repo runner %d: %w", id, err)
+	}
+	return nil
+}
+
+// RemoveEnterpriseRunner removes a self-hosted runner from an enterprise.
+// GitHub refuses while the runner is running a job.
+func (c *Client) RemoveEnterpriseRunner(ctx context.Context, enterprise string, id int64) error {
+	if _, err := c.gh.Enterprise.RemoveRunner(ctx, enterprise, id); err != nil {
+		return fmt.Errorf("removing enterprise runner %d: %w", id, err)
+	}
+	return nil
+}
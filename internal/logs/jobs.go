@@ -0,0 +1,137 @@
+package logs
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	jobStartRe = regexp.MustCompile(`Running job: (.+)$`)
+	jobEndRe   = regexp.MustCompile(`Job (.+) completed with result: (\w+)`)
+)
+
+// ResultInterrupted is the result of a job whose runner started another job
+// before the job's completion line, e.g. after the runner restarted.
+const ResultInterrupted = "Interrupted"
+
+// Job is a single job execution reconstructed from runner output.
+type Job struct {
+	Runner string
+	Name   string
+	Start  time.Time // zero if the start line fell outside the log window
+	End    time.Time // zero while the job is still running
+	Result string    // Succeeded, Failed, Canceled, ...; empty while running
+}
+
+// Running reports whether no completion line has been seen for the job.
+func (j Job) Running() bool {
+	return j.Result == ""
+}
+
+// Duration returns the job's run time, measured up to now for running jobs.
+func (j Job) Duration(now time.Time) time.Duration {
+	if j.Start.IsZero() {
+		return 0
+	}
+	end := j.End
+	if j.Running() {
+		end = now
+	}
+	return end.Sub(j.Start)
+}
+
+// ParseJobs extracts the job history from runner log lines. Lines may come
+// from several runners; the result is ordered by start time, or by end time
+// for jobs whose start line was not seen.
+func ParseJobs(lines []Line) []Job {
+	var jobs []Job
+	open := make(map[string]int) // runner -> index of its running job
+	for _, l := range lines {
+		if name, ok := jobStart(l.Text); ok {
+			// A runner runs one job at a time, so a job still open when
+			// the next one starts did not complete.
+			if i, ok := open[l.Runner]; ok {
+				jobs[i].End = l.Time
+				jobs[i].Result = ResultInterrupted
+			}
+			open[l.Runner] = len(jobs)
+			jobs = append(jobs, Job{Runner: l.Runner, Name: name, Start: l.Time})
+			continue
+		}
+		if name, result, ok := jobEnd(l.Text); ok {
+			if i, ok := open[l.Runner]; ok && jobs[i].Name == name {
+				jobs[i].End = l.Time
+				jobs[i].Result = result
+				delete(open, l.Runner)
+				continue
+			}
+			// The start line fell outside the log window.
+			jobs = append(jobs, Job{Runner: l.Runner, Name: name, End: l.Time, Result: result})
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].sortTime().Before(jobs[j].sortTime())
+	})
+	return jobs
+}
+
+// sortTime returns the job's start time, or its end time if the start line
+// was not seen.
+func (j Job) sortTime() time.Time {
+	if j.Start.IsZero() {
+		return j.End
+	}
+	return j.Start
+}
+
+// JobFilter keeps only the lines belonging to a named job, from its
+// "Running job" line through its completion line. It tracks state per
+// runner, so lines must be fed in order for each runner. It is safe for
+// concurrent use.
+type JobFilter struct {
+	name   string
+	mu     sync.Mutex
+	inside map[string]bool
+}
+
+// NewJobFilter creates a filter for the job with the given name.
+func NewJobFilter(name string) *JobFilter {
+	return &JobFilter{name: name, inside: make(map[string]bool)}
+}
+
+// Keep reports whether l is part of the job's section.
+func (f *JobFilter) Keep(l Line) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if name, ok := jobStart(l.Text); ok {
+		f.inside[l.Runner] = name == f.name
+		return f.inside[l.Runner]
+	}
+	if f.inside[l.Runner] {
+		if name, _, ok := jobEnd(l.Text); ok && name == f.name {
+			f.inside[l.Runner] = false
+		}
+		return true
+	}
+	return false
+}
+
+func jobStart(text string) (string, bool) {
+	m := jobStartRe.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	return strings.TrimSpace(m[1]), true
+}
+
+func jobEnd(text string) (string, string, bool) {
+	m := jobEndRe.FindStringSubmatch(text)
+	if m == nil {
+		return "", "", false
+	}
+	return strings.TrimSpace(m[1]), m[2], true
+}
@@ -0,0 +1,101 @@
+package logs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseJobs(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(s int) time.Time { return base.Add(time.Duration(s) * time.Second) }
+
+	lines := []Line{
+		{Runner: "r1", Time: at(0), Text: "√ Connected to GitHub"},
+		{Runner: "r1", Time: at(10), Text: "2024-01-01 12:00:10Z: Running job: build"},
+		{Runner: "r2", Time: at(15), Text: "2024-01-01 12:00:15Z: Running job: test (ubuntu, 18)"},
+		{Runner: "r1", Time: at(70), Text: "2024-01-01 12:01:10Z: Job build completed with result: Succeeded"},
+		{Runner: "r1", Time: at(80), Text: "2024-01-01 12:01:20Z: Running job: lint"},
+		{Runner: "r2", Time: at(95), Text: "2024-01-01 12:01:35Z: Job test (ubuntu, 18) completed with result: Failed"},
+	}
+
+	jobs := ParseJobs(lines)
+	if len(jobs) != 3 {
+		t.Fatalf("ParseJobs() returned %d jobs, want 3: %+v", len(jobs), jobs)
+	}
+
+	if j := jobs[0]; j.Runner != "r1" || j.Name != "build" || j.Result != "Succeeded" || j.Duration(at(1000)) != time.Minute {
+		t.Errorf("jobs[0] = %+v", j)
+	}
+	if j := jobs[1]; j.Runner != "r2" || j.Name != "test (ubuntu, 18)" || j.Result != "Failed" || j.Duration(at(1000)) != 80*time.Second {
+		t.Errorf("jobs[1] = %+v", j)
+	}
+	if j := jobs[2]; j.Name != "lint" || !j.Running() || j.Duration(at(100)) != 20*time.Second {
+		t.Errorf("jobs[2] = %+v", j)
+	}
+}
+
+func TestParseJobsIncomplete(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(s int) time.Time { return base.Add(time.Duration(s) * time.Second) }
+
+	lines := []Line{
+		{Runner: "r2", Time: at(5), Text: "Running job: lint"},
+		{Runner: "r1", Time: at(10), Text: "Running job: build"},
+		// The runner restarted and took a new job without completing build.
+		{Runner: "r1", Time: at(40), Text: "Running job: deploy"},
+		// Only the completion line of r3's job is within the window.
+		{Runner: "r3", Time: at(50), Text: "Job test completed with result: Succeeded"},
+		{Runner: "r1", Time: at(60), Text: "Job deploy completed with result: Succeeded"},
+	}
+
+	jobs := ParseJobs(lines)
+	var got []string
+	for _, j := range jobs {
+		got = append(got, j.Runner+"/"+j.Name)
+	}
+	want := []string{"r2/lint", "r1/build", "r1/deploy", "r3/test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseJobs() order = %v, want %v", got, want)
+	}
+	if j := jobs[1]; j.Running() || j.Result != ResultInterrupted || !j.End.Equal(at(40)) {
+		t.Errorf("interrupted job = %+v, want result %s ending at the next start", j, ResultInterrupted)
+	}
+	if j := jobs[2]; j.Result != "Succeeded" || j.Duration(at(1000)) != 20*time.Second {
+		t.Errorf("jobs[2] = %+v", j)
+	}
+	if !jobs[0].Running() {
+		t.Errorf("jobs[0] = %+v, want running", jobs[0])
+	}
+}
+
+func TestJobFilter(t *testing.T) {
+	lines := []Line{
+		{Runner: "r1", Text: "Listening for Jobs"},
+		{Runner: "r1", Text: "Running job: build"},
+		{Runner: "r2", Text: "Running job: test"},
+		{Runner: "r1", Text: "compiling"},
+		{Runner: "r2", Text: "testing"},
+		{Runner: "r1", Text: "Job build completed with result: Succeeded"},
+		{Runner: "r1", Text: "Listening for Jobs"},
+		{Runner: "r2", Text: "Job test completed with result: Succeeded"},
+	}
+
+	f := NewJobFilter("build")
+	var got []string
+	for _, l := range lines {
+		if f.Keep(l) {
+			got = append(got, l.Text)
+		}
+	}
+
+	want := []string{"Running job: build", "compiling", "Job build completed with result: Succeeded"}
+	if len(got) != len(want) {
+		t.Fatalf("JobFilter kept %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("kept[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package logs
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Line is a single log line from a runner container.
+type Line struct {
+	Runner string
+	Time   time.Time // zero if the line carried no Docker timestamp
+	Text   string
+	Stderr bool
+}
+
+// Copy writes a Docker log stream to stdout and stderr. Streams from
+// containers without a TTY carry 8-byte frame headers and are demultiplexed;
+// TTY streams are raw and copied to stdout as-is.
+func Copy(stdout, stderr io.Writer, r io.Reader, tty bool) error {
+	if tty {
+		_, err := io.Copy(stdout, r)
+		return err
+	}
+	_, err := stdcopy.StdCopy(stdout, stderr, r)
+	return err
+}
+
+// Read demultiplexes a Docker log stream and calls fn for every complete
+// line. The stream is expected to have been requested with timestamps so
+// lines can be ordered across runners.
+func Read(runner string, r io.Reader, tty bool, fn func(Line)) error {
+	stdout := &lineWriter{runner: runner, fn: fn}
+	stderr := &lineWriter{runner: runner, stderr: true, fn: fn}
+	err := Copy(stdout, stderr, r, tty)
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+// SortByTime orders lines by timestamp, keeping the original order for lines
+// with equal (or missing) timestamps.
+func SortByTime(lines []Line) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time.Before(lines[j].Time)
+	})
+}
+
+// parseLine splits a Docker-timestamped line into its time and text.
+func parseLine(raw string) (time.Time, string) {
+	ts, text, ok := strings.Cut(raw, " ")
+	if !ok {
+		ts, text = raw, ""
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, raw
+	}
+	return t, text
+}
+
+// lineWriter buffers partial writes and emits one Line per newline.
+type lineWriter struct {
+	runner string
+	stderr bool
+	fn     func(Line)
+	buf    []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(bytes.TrimRight(w.buf[:i], "\r")))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() {
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *lineWriter) emit(raw string) {
+	t, text := parseLine(raw)
+	w.fn(Line{Runner: w.runner, Time: t, Text: text, Stderr: w.stderr})
+}
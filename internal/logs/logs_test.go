@@ -0,0 +1,94 @@
+package logs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func TestRead(t *testing.T) {
+	var stream bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&stream, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&stream, stdcopy.Stderr)
+	stdout.Write([]byte("2024-01-01T00:00:01.000000000Z first\n2024-01-01T00:00:0"))
+	stderr.Write([]byte("2024-01-01T00:00:02.000000000Z oops\n"))
+	stdout.Write([]byte("3.000000000Z second\n"))
+
+	var got []Line
+	if err := Read("ghr-runner-1", &stream, false, func(l Line) { got = append(got, l) }); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	want := []struct {
+		text   string
+		stderr bool
+		sec    int
+	}{
+		{"first", false, 1},
+		{"oops", true, 2},
+		{"second", false, 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Read() returned %d lines, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Text != w.text || got[i].Stderr != w.stderr || got[i].Time.Second() != w.sec {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], w)
+		}
+		if got[i].Runner != "ghr-runner-1" {
+			t.Errorf("line %d runner = %q", i, got[i].Runner)
+		}
+	}
+}
+
+func TestSortByTime(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lines := []Line{
+		{Runner: "a", Time: base.Add(2 * time.Second), Text: "a2"},
+		{Runner: "a", Time: base.Add(4 * time.Second), Text: "a4"},
+		{Runner: "b", Time: base.Add(1 * time.Second), Text: "b1"},
+		{Runner: "b", Time: base.Add(3 * time.Second), Text: "b3"},
+	}
+	SortByTime(lines)
+
+	want := []string{"b1", "a2", "b3", "a4"}
+	for i, w := range want {
+		if lines[i].Text != w {
+			t.Errorf("lines[%d] = %q, want %q", i, lines[i].Text, w)
+		}
+	}
+}
+
+func TestParseLineWithoutTimestamp(t *testing.T) {
+	ts, text := parseLine("plain output")
+	if !ts.IsZero() || text != "plain output" {
+		t.Errorf("parseLine() = %v, %q", ts, text)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	var stream bytes.Buffer
+	stdcopy.NewStdWriter(&stream, stdcopy.Stdout).Write([]byte("out\n"))
+	stdcopy.NewStdWriter(&stream, stdcopy.Stderr).Write([]byte("err\n"))
+
+	var stdout, stderr bytes.Buffer
+	if err := Copy(&stdout, &stderr, &stream, false); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if stdout.String() != "out\n" {
+		t.Errorf("stdout = %q, want %q", stdout.String(), "out\n")
+	}
+	if stderr.String() != "err\n" {
+		t.Errorf("stderr = %q, want %q", stderr.String(), "err\n")
+	}
+
+	stdout.Reset()
+	if err := Copy(&stdout, &stderr, bytes.NewBufferString("raw tty\n"), true); err != nil {
+		t.Fatalf("Copy() tty error = %v", err)
+	}
+	if stdout.String() != "raw tty\n" {
+		t.Errorf("tty stdout = %q, want %q", stdout.String(), "raw tty\n")
+	}
+}
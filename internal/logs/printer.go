@@ -0,0 +1,72 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// colors is the ANSI palette cycled through for runner prefixes, in the same
+// order docker compose uses.
+var colors = []string{"36", "33", "32", "35", "34", "96", "93", "92", "95", "94"}
+
+// Printer writes lines prefixed with the runner name, aligned and optionally
+// colored per runner. Stderr lines go to Err. It is safe for concurrent use.
+type Printer struct {
+	Out        io.Writer
+	Err        io.Writer
+	Color      bool
+	Timestamps bool
+
+	mu     sync.Mutex
+	width  int
+	colors map[string]string
+}
+
+// NewPrinter creates a printer for the given runner names. Colors are
+// assigned in the order the names are given.
+func NewPrinter(out, errOut io.Writer, runners []string, color bool) *Printer {
+	p := &Printer{Out: out, Err: errOut, Color: color, colors: make(map[string]string)}
+	for i, name := range runners {
+		if len(name) > p.width {
+			p.width = len(name)
+		}
+		p.colors[name] = colors[i%len(colors)]
+	}
+	return p
+}
+
+// Print writes a single prefixed line.
+func (p *Printer) Print(l Line) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prefix := fmt.Sprintf("%-*s |", p.width, l.Runner)
+	if p.Color {
+		prefix = "\x1b[" + p.colors[l.Runner] + "m" + prefix + "\x1b[0m"
+	}
+	text := l.Text
+	if p.Timestamps && !l.Time.IsZero() {
+		text = l.Time.Format(time.RFC3339Nano) + " " + text
+	}
+	w := p.Out
+	if l.Stderr {
+		w = p.Err
+	}
+	fmt.Fprintf(w, "%s %s\n", prefix, text)
+}
+
+// ColorEnabled reports whether ANSI colors should be used for f. Colors are
+// disabled when f is not a terminal or NO_COLOR is set.
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	b := "a\nb\nC\nd\ne\nf\ng\nh\ni\nj\nk\n"
+
+	var buf bytes.Buffer
+	PrintDiff(&buf, "old", "new", []byte(a), []byte(b))
+	want := `--- old
++++ new
+@@ -1,6 +1,6 @@
+ a
+ b
+-c
++C
+ d
+ e
+ f
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+	if buf.String() != want {
+		t.Errorf("PrintDiff() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	PrintDiff(&buf, "old", "new", []byte(a), []byte(a))
+	if buf.Len() != 0 {
+		t.Errorf("PrintDiff() of equal input = %q, want empty", buf.String())
+	}
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// Check statuses reported by ghr doctor.
+const (
+	CheckOK   = "ok"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+	CheckSkip = "skip"
+)
+
+// CheckResult is the outcome of a single ghr doctor check. Hint explains how
+// to fix a warning or failure.
+type CheckResult struct {
+	Name   string
+	Status string
+	Detail string
+	Hint   string
+}
+
+var checkMarks = map[string]string{
+	CheckOK:   "✓",
+	CheckWarn: "!",
+	CheckFail: "✗",
+	CheckSkip: "-",
+}
+
+// PrintChecks prints doctor results, one per line, with fix hints indented
+// below warnings and failures.
+func PrintChecks(w io.Writer, results []CheckResult) {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Name))
+	}
+	for _, r := range results {
+		fmt.Fprintf(w, "%s %-*s  %s\n", checkMarks[r.Status], width, r.Name, r.Detail)
+		if r.Hint != "" && (r.Status == CheckWarn || r.Status == CheckFail) {
+			fmt.Fprintf(w, "  %*s  → %s\n", width, "", r.Hint)
+		}
+	}
+}
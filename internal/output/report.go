@@ -0,0 +1,134 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/report"
+)
+
+// PrintReportTable prints a utilization report as a table.
+func PrintReportTable(w io.Writer, r report.Report) {
+	fmt.Fprintf(w, "Window: %s - %s\n\n", formatTime(r.Start), formatTime(r.End))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUNNER\tJOBS\tBUSY\tBUSY %\tJOBS/H\tAVG WAIT\tMAX WAIT")
+	fmt.Fprintln(tw, "------\t----\t----\t------\t------\t--------\t--------")
+	for _, s := range r.Runners {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%.1f\t%.2f\t%s\t%s\n",
+			s.Runner, s.Jobs, formatDuration(s.Busy), s.BusyPct, s.JobsPerHour,
+			formatDuration(s.AvgQueueWait), formatDuration(s.MaxQueueWait))
+	}
+	f := r.Fleet
+	fmt.Fprintf(tw, "TOTAL\t%d\t%s\t%.1f\t%.2f\t%s\t%s\n",
+		f.Jobs, formatDuration(f.Busy), f.BusyPct, f.JobsPerHour,
+		formatDuration(f.AvgQueueWait), formatDuration(f.MaxQueueWait))
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nRunners: %d\n", f.Runners)
+	if f.PeakConcurrency > 0 {
+		fmt.Fprintf(w, "Peak concurrency: %d (at %s)\n", f.PeakConcurrency, formatTime(f.PeakAt))
+	} else {
+		fmt.Fprintln(w, "Peak concurrency: 0")
+	}
+}
+
+// WriteReportCSV writes one row per runner followed by a TOTAL row.
+// Durations are in seconds.
+func WriteReportCSV(w io.Writer, r report.Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"runner", "jobs", "busy_seconds", "busy_pct", "jobs_per_hour",
+		"avg_queue_wait_seconds", "max_queue_wait_seconds", "peak_concurrency"})
+	for _, s := range r.Runners {
+		cw.Write([]string{
+			s.Runner, strconv.Itoa(s.Jobs), seconds(s.Busy), pct(s.BusyPct),
+			rate(s.JobsPerHour), seconds(s.AvgQueueWait), seconds(s.MaxQueueWait), "",
+		})
+	}
+	f := r.Fleet
+	cw.Write([]string{
+		"TOTAL", strconv.Itoa(f.Jobs), seconds(f.Busy), pct(f.BusyPct),
+		rate(f.JobsPerHour), seconds(f.AvgQueueWait), seconds(f.MaxQueueWait),
+		strconv.Itoa(f.PeakConcurrency),
+	})
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteReportJSON writes the report as indented JSON. Durations are in seconds.
+func WriteReportJSON(w io.Writer, r report.Report) error {
+	type runnerJSON struct {
+		Runner              string  `json:"runner"`
+		Jobs                int     `json:"jobs"`
+		BusySeconds         float64 `json:"busy_seconds"`
+		BusyPct             float64 `json:"busy_pct"`
+		JobsPerHour         float64 `json:"jobs_per_hour"`
+		AvgQueueWaitSeconds float64 `json:"avg_queue_wait_seconds"`
+		MaxQueueWaitSeconds float64 `json:"max_queue_wait_seconds"`
+	}
+	type fleetJSON struct {
+		Runners             int        `json:"runners"`
+		Jobs                int        `json:"jobs"`
+		BusySeconds         float64    `json:"busy_seconds"`
+		BusyPct             float64    `json:"busy_pct"`
+		JobsPerHour         float64    `json:"jobs_per_hour"`
+		AvgQueueWaitSeconds float64    `json:"avg_queue_wait_seconds"`
+		MaxQueueWaitSeconds float64    `json:"max_queue_wait_seconds"`
+		PeakConcurrency     int        `json:"peak_concurrency"`
+		PeakAt              *time.Time `json:"peak_at,omitempty"`
+	}
+
+	out := struct {
+		Start   time.Time    `json:"start"`
+		End     time.Time    `json:"end"`
+		Runners []runnerJSON `json:"runners"`
+		Fleet   fleetJSON    `json:"fleet"`
+	}{Start: r.Start, End: r.End, Runners: []runnerJSON{}}
+
+	for _, s := range r.Runners {
+		out.Runners = append(out.Runners, runnerJSON{
+			Runner:              s.Runner,
+			Jobs:                s.Jobs,
+			BusySeconds:         s.Busy.Seconds(),
+			BusyPct:             s.BusyPct,
+			JobsPerHour:         s.JobsPerHour,
+			AvgQueueWaitSeconds: s.AvgQueueWait.Seconds(),
+			MaxQueueWaitSeconds: s.MaxQueueWait.Seconds(),
+		})
+	}
+	f := r.Fleet
+	out.Fleet = fleetJSON{
+		Runners:             f.Runners,
+		Jobs:                f.Jobs,
+		BusySeconds:         f.Busy.Seconds(),
+		BusyPct:             f.BusyPct,
+		JobsPerHour:         f.JobsPerHour,
+		AvgQueueWaitSeconds: f.AvgQueueWait.Seconds(),
+		MaxQueueWaitSeconds: f.MaxQueueWait.Seconds(),
+		PeakConcurrency:     f.PeakConcurrency,
+	}
+	if !f.PeakAt.IsZero() {
+		out.Fleet.PeakAt = &f.PeakAt
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 0, 64)
+}
+
+func pct(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func rate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
@@ -3,20 +3,73 @@ package output
 import (
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/logs"
 	"github.com/lamtuanvu/gh-runner-ctl/internal/runner"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/schedule"
 )
 
-// PrintRunnerTable prints a formatted table of runner info.
+// PrintRunnerTable prints a formatted table of runner info. A HOST column is
+// added when the runners are spread over several hosts, and a REPO column
+// when they serve the repos of a repos: list. A LABELS column shows the
+// custom labels of runners changed with `ghr label`.
 func PrintRunnerTable(w io.Writer, runners []runner.RunnerInfo, showGitHub bool) {
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
 
+	showHost, showRepo, showLabels := false, false, false
+	for _, r := range runners {
+		if r.Relabeled {
+			showLabels = true
+		}
+		if r.Host != "" {
+			showHost = true
+		}
+		if r.Repo != "" {
+			showRepo = true
+		}
+	}
+	var cols []string
+	if showRepo {
+		cols = append(cols, "REPO")
+	}
+	cols = append(cols, "NUM", "NAME")
+	if showHost {
+		cols = append(cols, "HOST")
+	}
+	cols = append(cols, "CONTAINER")
 	if showGitHub {
-		fmt.Fprintln(tw, "NUM\tNAME\tCONTAINER\tDOCKER STATUS\tGITHUB\tBUSY")
-		fmt.Fprintln(tw, "---\t----\t---------\t-------------\t------\t----")
-		for _, r := range runners {
+		cols = append(cols, "DOCKER STATUS", "GITHUB", "BUSY")
+	} else {
+		cols = append(cols, "STATUS")
+	}
+	if showLabels {
+		cols = append(cols, "LABELS")
+	}
+	printRow(tw, cols)
+	dashes := make([]string, len(cols))
+	for i, c := range cols {
+		dashes[i] = strings.Repeat("-", len(c))
+	}
+	printRow(tw, dashes)
+
+	for _, r := range runners {
+		var row []string
+		if showRepo {
+			row = append(row, r.Repo)
+		}
+		row = append(row, fmt.Sprintf("%d", r.Num), r.Name)
+		if showHost {
+			row = append(row, r.Host)
+		}
+		row = append(row, r.ContainerID, statusWithState(r))
+		if showGitHub {
 			busy := ""
 			if r.GitHubStatus != "" {
 				if r.Busy {
@@ -25,20 +78,143 @@ func PrintRunnerTable(w io.Writer, runners []runner.RunnerInfo, showGitHub bool)
 					busy = "no"
 				}
 			}
-			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n",
-				r.Num, r.Name, r.ContainerID, statusWithState(r), r.GitHubStatus, busy)
+			row = append(row, r.GitHubStatus, busy)
 		}
-	} else {
-		fmt.Fprintln(tw, "NUM\tNAME\tCONTAINER\tSTATUS")
-		fmt.Fprintln(tw, "---\t----\t---------\t------")
-		for _, r := range runners {
-			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n",
-				r.Num, r.Name, r.ContainerID, statusWithState(r))
+		if showLabels {
+			row = append(row, runnerLabels(r))
 		}
+		printRow(tw, row)
 	}
 	tw.Flush()
 }
 
+// runnerLabels returns the labels of a runner changed with `ghr label`, or
+// "-" for a runner that still has its configured labels. Labels GitHub
+// reports instead are shown after them.
+func runnerLabels(r runner.RunnerInfo) string {
+	switch {
+	case !r.Relabeled:
+		return "-"
+	}
+	labels := orNone(r.Labels)
+	if r.LabelsDrift {
+		labels += " (GitHub: " + orNone(r.GitHubLabels) + ")"
+	}
+	return labels
+}
+
+func orNone(labels []string) string {
+	if len(labels) == 0 {
+		return "(none)"
+	}
+	return strings.Join(labels, ",")
+}
+
+func printRow(w io.Writer, cells []string) {
+	fmt.Fprintln(w, strings.Join(cells, "\t"))
+}
+
+// PrintPlanTable prints the runners `ghr up --dry-run` would create, with
+// their templates rendered. A HOST column is added in fleet mode.
+func PrintPlanTable(w io.Writer, plan []runner.Planned) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	showHost := false
+	for _, p := range plan {
+		if p.Host != "" {
+			showHost = true
+		}
+	}
+	cols := []string{"NUM", "NAME"}
+	if showHost {
+		cols = append(cols, "HOST")
+	}
+	cols = append(cols, "LABELS", "EXTRA ENV")
+	printRow(tw, cols)
+	dashes := make([]string, len(cols))
+	for i, c := range cols {
+		dashes[i] = strings.Repeat("-", len(c))
+	}
+	printRow(tw, dashes)
+
+	for _, p := range plan {
+		row := []string{fmt.Sprintf("%d", p.Num), p.Name}
+		if showHost {
+			row = append(row, p.Host)
+		}
+		var env []string
+		for k, v := range p.Config.Runners.ExtraEnv {
+			env = append(env, k+"="+v)
+		}
+		sort.Strings(env)
+		row = append(row, orDash(strings.Join(p.Config.Runners.Labels, ",")), orDash(strings.Join(env, " ")))
+		printRow(tw, row)
+	}
+	tw.Flush()
+}
+
+// PrintJobTable prints the job history parsed from runner logs.
+func PrintJobTable(w io.Writer, jobs []logs.Job, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUNNER\tJOB\tSTARTED\tFINISHED\tDURATION\tRESULT")
+	fmt.Fprintln(tw, "------\t---\t-------\t--------\t--------\t------")
+	for _, j := range jobs {
+		result := j.Result
+		if j.Running() {
+			result = "running"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			j.Runner, j.Name, formatTime(j.Start), formatTime(j.End),
+			formatDuration(j.Duration(now)), result)
+	}
+	tw.Flush()
+}
+
+// PrintHistoryTable prints workflow jobs fetched from the GitHub API.
+func PrintHistoryTable(w io.Writer, jobs []ghclient.WorkflowJob) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUNNER\tREPO\tWORKFLOW\tJOB\tCONCLUSION\tDURATION\tURL")
+	fmt.Fprintln(tw, "------\t----\t--------\t---\t----------\t--------\t---")
+	for _, j := range jobs {
+		conclusion := j.Conclusion
+		if conclusion == "" {
+			conclusion = j.Status
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			j.RunnerName, j.Repo, j.Workflow, j.Name, conclusion,
+			formatDuration(j.Duration()), j.HTMLURL)
+	}
+	tw.Flush()
+}
+
+// PrintTransitionTable prints upcoming schedule transitions. Times are shown
+// in each schedule's own time zone.
+func PrintTransitionTable(w io.Writer, transitions []schedule.Transition) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tIN\tSCHEDULE\tRUNNERS")
+	fmt.Fprintln(tw, "----\t--\t--------\t-------")
+	now := time.Now()
+	for _, t := range transitions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n",
+			t.At.Format("Mon 2006-01-02 15:04 MST"), formatDuration(t.At.Sub(now).Truncate(time.Minute)),
+			t.Entry.Name, t.Entry.Count)
+	}
+	tw.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
+
 func statusWithState(r runner.RunnerInfo) string {
 	if r.DockerStatus != "" {
 		return r.DockerStatus
@@ -47,19 +223,142 @@ func statusWithState(r runner.RunnerInfo) string {
 }
 
 // PrintStatusSummary prints the ghr status overview.
-func PrintStatusSummary(w io.Writer, configPath string, scope, org, repoOwner, repoName string,
+func PrintStatusSummary(w io.Writer, configPath, githubURL, scope, target string,
 	image string, labels []string, total, running, stopped int) {
 
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
 	fmt.Fprintf(tw, "Config:\t%s\n", configPath)
+	fmt.Fprintf(tw, "GitHub:\t%s\n", githubURL)
 	fmt.Fprintf(tw, "Scope:\t%s\n", scope)
-	if scope == "org" {
-		fmt.Fprintf(tw, "Org:\t%s\n", org)
-	} else {
-		fmt.Fprintf(tw, "Repo:\t%s/%s\n", repoOwner, repoName)
+	switch scope {
+	case "org":
+		fmt.Fprintf(tw, "Org:\t%s\n", target)
+	case "enterprise":
+		fmt.Fprintf(tw, "Enterprise:\t%s\n", target)
+	default:
+		fmt.Fprintf(tw, "Repo:\t%s\n", target)
 	}
 	fmt.Fprintf(tw, "Image:\t%s\n", image)
 	fmt.Fprintf(tw, "Labels:\t%s\n", strings.Join(labels, ", "))
 	fmt.Fprintf(tw, "Runners:\t%d total (%d running, %d stopped)\n", total, running, stopped)
 	tw.Flush()
 }
+
+// HostRow is one line of the fleet host table.
+type HostRow struct {
+	Name       string
+	Endpoint   string
+	Weight     int
+	MaxRunners int
+	Running    int
+	Stopped    int
+}
+
+// PrintHostTable prints the runner counts per fleet host.
+func PrintHostTable(w io.Writer, hosts []HostRow) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tENDPOINT\tWEIGHT\tMAX\tRUNNING\tSTOPPED")
+	fmt.Fprintln(tw, "----\t--------\t------\t---\t-------\t-------")
+	for _, h := range hosts {
+		maxRunners := "-"
+		if h.MaxRunners > 0 {
+			maxRunners = fmt.Sprintf("%d", h.MaxRunners)
+		}
+		weight := h.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%d\t%d\n", h.Name, h.Endpoint, weight, maxRunners, h.Running, h.Stopped)
+	}
+	tw.Flush()
+}
+
+// PrintConfigSources prints each resolved config value and the layer that
+// set it.
+func PrintConfigSources(w io.Writer, entries []config.Entry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	fmt.Fprintln(tw, "---\t-----\t------")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Key, formatValue(e.Value), e.Source)
+	}
+	tw.Flush()
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case nil:
+		return ""
+	}
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0 {
+		return ""
+	}
+	if rv.Kind() == reflect.Slice {
+		return fmt.Sprintf("(%d entries)", rv.Len())
+	}
+	return fmt.Sprint(v)
+}
+
+// PrintContextTable prints named contexts, marking the current one with "*".
+// Literal tokens are redacted.
+func PrintContextTable(w io.Writer, contexts []config.Context, current string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CURRENT\tNAME\tCONFIG\tDOCKER HOST\tTOKEN")
+	fmt.Fprintln(tw, "-------\t----\t------\t-----------\t-----")
+	for _, c := range contexts {
+		marker := ""
+		if c.Name == current {
+			marker = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", marker, c.Name,
+			orDash(c.Config), orDash(c.DockerHost), orDash(config.RedactToken(c.Token)))
+	}
+	tw.Flush()
+}
+
+// PrintGroupTable prints org runner groups, marking the one runners join
+// (runners.group) with "*". repos holds the repositories of groups with
+// "selected" visibility.
+func PrintGroupTable(w io.Writer, groups []ghclient.RunnerGroup, repos map[int64][]string, current string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CURRENT\tNAME\tVISIBILITY\tREPOS\tWORKFLOWS\tPUBLIC REPOS")
+	fmt.Fprintln(tw, "-------\t----\t----------\t-----\t---------\t------------")
+	for _, g := range groups {
+		marker := ""
+		if g.Name == current {
+			marker = "*"
+		}
+		name := g.Name
+		if g.Default {
+			name += " (default)"
+		}
+		access := "all"
+		switch g.Visibility {
+		case "selected":
+			access = orDash(strings.Join(repos[g.ID], ","))
+		case "private":
+			access = "private"
+		}
+		workflows := "any"
+		if len(g.Workflows) > 0 {
+			workflows = strings.Join(g.Workflows, ",")
+		}
+		public := "no"
+		if g.AllowsPublicRepos {
+			public = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", marker, name, g.Visibility,
+			access, workflows, public)
+	}
+	tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+)
+
+// RunnerStats summarizes one runner's activity over the report window.
+type RunnerStats struct {
+	Runner       string
+	Jobs         int
+	Busy         time.Duration
+	BusyPct      float64
+	JobsPerHour  float64
+	AvgQueueWait time.Duration
+	MaxQueueWait time.Duration
+}
+
+// FleetStats summarizes all runners over the report window.
+type FleetStats struct {
+	Runners         int
+	Jobs            int
+	Busy            time.Duration
+	BusyPct         float64 // busy time over runners x window
+	JobsPerHour     float64
+	AvgQueueWait    time.Duration
+	MaxQueueWait    time.Duration
+	PeakConcurrency int
+	PeakAt          time.Time
+}
+
+// Report is a utilization report for a time window.
+type Report struct {
+	Start   time.Time
+	End     time.Time
+	Runners []RunnerStats
+	Fleet   FleetStats
+}
+
+// Compute builds a report from workflow jobs over [start, end). Busy time is
+// clipped to the window; jobs still in progress count as busy until end.
+// Runners lists runners that should be counted for capacity even if they ran
+// no jobs; runners seen in jobs are added automatically.
+func Compute(jobs []ghclient.WorkflowJob, runners []string, start, end time.Time) Report {
+	window := end.Sub(start)
+	hours := window.Hours()
+
+	stats := make(map[string]*RunnerStats)
+	waits := make(map[string][]time.Duration)
+	for _, name := range runners {
+		stats[name] = &RunnerStats{Runner: name}
+	}
+
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	var events []event
+	var fleetWaits []time.Duration
+
+	for _, j := range jobs {
+		if j.RunnerName == "" || j.StartedAt.IsZero() {
+			continue
+		}
+		s, ok := stats[j.RunnerName]
+		if !ok {
+			s = &RunnerStats{Runner: j.RunnerName}
+			stats[j.RunnerName] = s
+		}
+
+		jobEnd := j.CompletedAt
+		if jobEnd.IsZero() || jobEnd.After(end) {
+			jobEnd = end
+		}
+		jobStart := j.StartedAt
+		if jobStart.Before(start) {
+			jobStart = start
+		}
+		if !jobEnd.After(jobStart) {
+			continue
+		}
+
+		s.Jobs++
+		s.Busy += jobEnd.Sub(jobStart)
+		events = append(events, event{jobStart, 1}, event{jobEnd, -1})
+
+		if !j.CreatedAt.IsZero() && j.StartedAt.After(j.CreatedAt) {
+			wait := j.StartedAt.Sub(j.CreatedAt)
+			waits[j.RunnerName] = append(waits[j.RunnerName], wait)
+			fleetWaits = append(fleetWaits, wait)
+		}
+	}
+
+	r := Report{Start: start, End: end}
+	for _, s := range stats {
+		if window > 0 {
+			s.BusyPct = 100 * float64(s.Busy) / float64(window)
+		}
+		if hours > 0 {
+			s.JobsPerHour = float64(s.Jobs) / hours
+		}
+		s.AvgQueueWait, s.MaxQueueWait = waitStats(waits[s.Runner])
+
+		r.Runners = append(r.Runners, *s)
+		r.Fleet.Jobs += s.Jobs
+		r.Fleet.Busy += s.Busy
+	}
+	sort.Slice(r.Runners, func(i, j int) bool {
+		return r.Runners[i].Runner < r.Runners[j].Runner
+	})
+
+	r.Fleet.Runners = len(r.Runners)
+	if window > 0 && r.Fleet.Runners > 0 {
+		r.Fleet.BusyPct = 100 * float64(r.Fleet.Busy) / (float64(window) * float64(r.Fleet.Runners))
+	}
+	if hours > 0 {
+		r.Fleet.JobsPerHour = float64(r.Fleet.Jobs) / hours
+	}
+	r.Fleet.AvgQueueWait, r.Fleet.MaxQueueWait = waitStats(fleetWaits)
+
+	// Sweep start/end events to find peak concurrency. Ends sort before
+	// starts at the same instant so back-to-back jobs don't overlap.
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+	current := 0
+	for _, e := range events {
+		current += e.delta
+		if current > r.Fleet.PeakConcurrency {
+			r.Fleet.PeakConcurrency = current
+			r.Fleet.PeakAt = e.at
+		}
+	}
+	return r
+}
+
+func waitStats(waits []time.Duration) (avg, max time.Duration) {
+	if len(waits) == 0 {
+		return 0, 0
+	}
+	var total time.Duration
+	for _, w := range waits {
+		total += w
+		if w > max {
+			max = w
+		}
+	}
+	return total / time.Duration(len(waits)), max
+}
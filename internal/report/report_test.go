@@ -0,0 +1,57 @@
+package report
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+)
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Hour)
+	at := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+
+	jobs := []ghclient.WorkflowJob{
+		// r1: 60m busy, waited 2m
+		{RunnerName: "r1", CreatedAt: at(58), StartedAt: at(60), CompletedAt: at(120)},
+		// r2: overlaps r1 for 30m, waited 4m
+		{RunnerName: "r2", CreatedAt: at(86), StartedAt: at(90), CompletedAt: at(150)},
+		// r1: runs past the end of the window and is clipped to 30m
+		{RunnerName: "r1", CreatedAt: at(570), StartedAt: at(570), CompletedAt: at(700)},
+		// queued jobs are ignored
+		{RunnerName: "", CreatedAt: at(10)},
+	}
+
+	r := Compute(jobs, []string{"r1", "r2", "r3"}, start, end)
+
+	if r.Fleet.Runners != 3 {
+		t.Errorf("Fleet.Runners = %d, want 3", r.Fleet.Runners)
+	}
+	if r.Fleet.Jobs != 3 {
+		t.Errorf("Fleet.Jobs = %d, want 3", r.Fleet.Jobs)
+	}
+	if r.Fleet.PeakConcurrency != 2 || !r.Fleet.PeakAt.Equal(at(90)) {
+		t.Errorf("peak = %d at %v, want 2 at %v", r.Fleet.PeakConcurrency, r.Fleet.PeakAt, at(90))
+	}
+	if r.Fleet.MaxQueueWait != 4*time.Minute || r.Fleet.AvgQueueWait != 3*time.Minute {
+		t.Errorf("fleet queue wait avg=%v max=%v, want 3m/4m", r.Fleet.AvgQueueWait, r.Fleet.MaxQueueWait)
+	}
+
+	// 60 + 30 (clipped) + 60 = 150 busy minutes over 3 runners x 600 minutes.
+	if got, want := r.Fleet.BusyPct, 100*150.0/1800.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Fleet.BusyPct = %v, want %v", got, want)
+	}
+
+	byName := make(map[string]RunnerStats)
+	for _, s := range r.Runners {
+		byName[s.Runner] = s
+	}
+	if s := byName["r1"]; s.Jobs != 2 || s.Busy != 90*time.Minute || s.JobsPerHour != 0.2 {
+		t.Errorf("r1 = %+v", s)
+	}
+	if s := byName["r3"]; s.Jobs != 0 || s.Busy != 0 {
+		t.Errorf("r3 = %+v", s)
+	}
+}
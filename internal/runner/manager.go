@@ -3,60 +3,261 @@ package runner
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
 	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
 )
 
-// Manager orchestrates runner lifecycle operations.
+// Host is a Docker host runners are placed on. Name is empty unless the
+// config lists hosts (fleet mode).
+type Host struct {
+	Name       string
+	Runtime    Runtime
+	Weight     int
+	MaxRunners int
+	// Socket overrides docker.socket for containers on this host.
+	Socket string
+}
+
+// Manager orchestrates runner lifecycle operations across one or more hosts.
+// Runner numbers are unique across all hosts, so runner names stay unique on
+// GitHub.
 type Manager struct {
-	Docker *docker.Client
+	Hosts  []*Host
 	Config *config.Config
+
+	// pool is set on the managers returned by Pools, which see only the
+	// runners of Config.Repo.
+	pool bool
+	// root is the config given to NewManager. Containers are listed for it,
+	// so pools still number their runners across every repo in repos:.
+	root *config.Config
 }
 
 // NewManager creates a new runner manager.
-func NewManager(cfg *config.Config, dc *docker.Client) *Manager {
-	return &Manager{Config: cfg, Docker: dc}
+func NewManager(cfg *config.Config, hosts []*Host) *Manager {
+	return &Manager{Config: cfg, Hosts: hosts, root: cfg}
 }
 
-// Up creates and starts `count` new runners, filling the lowest available numbers.
-func (m *Manager) Up(ctx context.Context, count int) ([]string, error) {
-	token, err := config.ResolveToken(m.Config.Token)
+// Fleet reports whether runners are spread over several named hosts.
+func (m *Manager) Fleet() bool {
+	return len(m.Config.Hosts) > 0
+}
+
+// Pools returns a Manager for each repo in repos:, configured with that
+// repo's runner settings and seeing only its runners, or m itself when
+// repos is empty. With repos given as "owner/name", only those are
+// returned, in the config's order.
+func (m *Manager) Pools(repos ...string) ([]*Manager, error) {
+	if len(m.Config.Repos) == 0 {
+		if len(repos) > 0 {
+			return nil, fmt.Errorf("selecting a repo needs a repos: list in the config")
+		}
+		return []*Manager{m}, nil
+	}
+	want := make(map[string]bool, len(repos))
+	for _, r := range repos {
+		want[strings.ToLower(r)] = true
+	}
+	var pools []*Manager
+	for _, cfg := range m.Config.Pools() {
+		name := strings.ToLower(cfg.Repo.FullName())
+		if len(repos) > 0 && !want[name] {
+			continue
+		}
+		delete(want, name)
+		pools = append(pools, &Manager{Hosts: m.Hosts, Config: cfg, pool: true, root: m.root})
+	}
+	for _, r := range repos {
+		if want[strings.ToLower(r)] {
+			return nil, fmt.Errorf("repo %q is not in repos:", r)
+		}
+	}
+	return pools, nil
+}
+
+// Close closes the runtime of every host.
+func (m *Manager) Close() {
+	for _, h := range m.Hosts {
+		h.Runtime.Close()
+	}
+}
+
+// Client returns the runtime of the named host, or of the first host if
+// there is no such host.
+func (m *Manager) Client(host string) Runtime {
+	for _, h := range m.Hosts {
+		if h.Name == host {
+			return h.Runtime
+		}
+	}
+	return m.Hosts[0].Runtime
+}
+
+// containers lists the managed containers of m on every host: those of its
+// repo for a pool manager, otherwise all of them.
+func (m *Manager) containers(ctx context.Context, skipFailed bool) ([]docker.RunnerContainer, error) {
+	all, err := m.allContainers(ctx, skipFailed)
+	if err != nil || !m.pool {
+		return all, err
+	}
+	return m.own(all), nil
+}
+
+// own returns the containers in list that belong to m's repo.
+func (m *Manager) own(list []docker.RunnerContainer) []docker.RunnerContainer {
+	if !m.pool {
+		return list
+	}
+	var mine []docker.RunnerContainer
+	for _, c := range list {
+		if c.Labels[docker.LabelRepoOwner] == m.Config.Repo.Owner && c.Labels[docker.LabelRepoName] == m.Config.Repo.Name {
+			mine = append(mine, c)
+		}
+	}
+	return mine
+}
+
+// allContainers lists the managed containers on every host, recording the
+// host on each. With skipFailed, hosts that cannot be reached are reported
+// on stderr and skipped instead of failing the whole listing.
+func (m *Manager) allContainers(ctx context.Context, skipFailed bool) ([]docker.RunnerContainer, error) {
+	var all []docker.RunnerContainer
+	for _, h := range m.Hosts {
+		list, err := h.Runtime.ListManagedContainers(ctx, m.root)
+		if err != nil {
+			if h.Name != "" {
+				err = fmt.Errorf("host %s: %w", h.Name, err)
+			}
+			if skipFailed && len(m.Hosts) > 1 {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			return nil, err
+		}
+		for i := range list {
+			list[i].Host = h.Name
+		}
+		all = append(all, list...)
+	}
+	return all, nil
+}
+
+// loads returns the runner count of each host, in m.Hosts order.
+func (m *Manager) loads(existing []docker.RunnerContainer) []Load {
+	loads := make([]Load, len(m.Hosts))
+	for i, h := range m.Hosts {
+		loads[i] = Load{Weight: h.Weight, Max: h.MaxRunners}
+		for _, c := range existing {
+			if c.Host == h.Name {
+				loads[i].Count++
+			}
+		}
+	}
+	return loads
+}
+
+// Planned is a runner that Up would create.
+type Planned struct {
+	Num   int
+	Name  string
+	Host  string // fleet host name; empty unless hosts are configured
+	Index int    // the runner's {{.Index}}
+	// Config is the config the runner is created with, with its templates
+	// rendered and the host's socket applied.
+	Config *config.Config
+
+	host *Host
+}
+
+// Plan returns the `count` runners Up would create, numbered from the
+// lowest available numbers and placed on the least loaded hosts, counting
+// the runners of every repo. Each runner gets the lowest {{.Index}} not
+// used by its pool's runners on its host.
+func (m *Manager) Plan(ctx context.Context, count int) ([]Planned, error) {
+	all, err := m.allContainers(ctx, false)
 	if err != nil {
 		return nil, err
 	}
-
-	existing, err := m.Docker.ListManagedContainers(ctx)
+	placement, err := Place(m.loads(all), count)
 	if err != nil {
 		return nil, err
 	}
 
 	var nums []int
-	for _, c := range existing {
+	indexes := make(map[string][]int) // per host
+	for _, c := range m.own(all) {
 		nums = append(nums, c.Num)
+		// Runners created before indexes were recorded hold none.
+		if i, err := strconv.Atoi(c.Labels[docker.LabelRunnerIndex]); err == nil {
+			indexes[c.Host] = append(indexes[c.Host], i)
+		}
+	}
+	pool := ""
+	if m.pool {
+		pool = m.Config.Repo.Name
+	}
+
+	var plan []Planned
+	for i, num := range NextNumbers(nums, count) {
+		h := m.Hosts[placement[i]]
+		index := NextIndex(indexes[h.Name])
+		indexes[h.Name] = append(indexes[h.Name], index)
+		cfg, err := m.Config.Render(config.RunnerVars{Num: num, Host: h.Name, Pool: pool, Index: index})
+		if err != nil {
+			return nil, fmt.Errorf("runner %d: %w", num, err)
+		}
+		if h.Socket != "" {
+			cfg.Docker.Socket = h.Socket
+		}
+		plan = append(plan, Planned{
+			Num:    num,
+			Name:   fmt.Sprintf("%s-runner-%d", cfg.Runners.NamePrefix, num),
+			Host:   h.Name,
+			Index:  index,
+			Config: cfg,
+			host:   h,
+		})
+	}
+	return plan, nil
+}
+
+// Up creates and starts `count` new runners as planned by Plan.
+func (m *Manager) Up(ctx context.Context, count int) ([]string, error) {
+	token, err := config.ResolveToken(m.Config.Token)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := m.Plan(ctx, count)
+	if err != nil {
+		return nil, err
 	}
 
-	newNums := NextNumbers(nums, count)
 	var created []string
-	for _, num := range newNums {
-		name := fmt.Sprintf("%s-runner-%d", m.Config.Runners.NamePrefix, num)
-		fmt.Printf("Creating %s...\n", name)
-		id, err := m.Docker.CreateRunner(ctx, m.Config, num, token)
+	for _, p := range plan {
+		if p.Host != "" {
+			fmt.Printf("Creating %s on %s...\n", p.Name, p.Host)
+		} else {
+			fmt.Printf("Creating %s...\n", p.Name)
+		}
+		id, err := p.host.Runtime.CreateRunner(ctx, p.Config, p.Num, p.Index, token)
 		if err != nil {
-			return created, fmt.Errorf("creating runner %d: %w", num, err)
+			return created, fmt.Errorf("creating runner %d: %w", p.Num, err)
 		}
 		created = append(created, id)
-		fmt.Printf("  Started %s (%s)\n", name, id[:12])
+		fmt.Printf("  Started %s (%s)\n", p.Name, id[:12])
 	}
 	return created, nil
 }
 
-// Down stops and removes `count` runners, starting from the highest-numbered.
-// If all is true, removes all managed runners.
+// Down stops and removes `count` runners, starting from the highest-numbered
+// runner on the most loaded host. If all is true, removes all managed runners.
 func (m *Manager) Down(ctx context.Context, count int, all bool) error {
-	existing, err := m.Docker.ListManagedContainers(ctx)
+	existing, err := m.containers(ctx, false)
 	if err != nil {
 		return err
 	}
@@ -69,21 +270,30 @@ func (m *Manager) Down(ctx context.Context, count int, all bool) error {
 	if all {
 		targets = existing
 	} else {
-		var nums []int
-		numToContainer := make(map[int]docker.RunnerContainer)
-		for _, c := range existing {
-			nums = append(nums, c.Num)
-			numToContainer[c.Num] = c
+		// Each host's runners, highest-numbered first.
+		byHost := make([][]docker.RunnerContainer, len(m.Hosts))
+		for i, h := range m.Hosts {
+			var nums []int
+			numToContainer := make(map[int]docker.RunnerContainer)
+			for _, c := range existing {
+				if c.Host == h.Name {
+					nums = append(nums, c.Num)
+					numToContainer[c.Num] = c
+				}
+			}
+			for _, n := range HighestNumbers(nums, len(nums)) {
+				byHost[i] = append(byHost[i], numToContainer[n])
+			}
 		}
-		toRemove := HighestNumbers(nums, count)
-		for _, n := range toRemove {
-			targets = append(targets, numToContainer[n])
+		for _, i := range PickRemovals(m.loads(existing), count) {
+			targets = append(targets, byHost[i][0])
+			byHost[i] = byHost[i][1:]
 		}
 	}
 
 	for _, c := range targets {
 		fmt.Printf("Removing %s...\n", c.Name)
-		if err := m.Docker.RemoveRunner(ctx, c.Name); err != nil {
+		if err := m.Client(c.Host).RemoveRunner(ctx, c.Name); err != nil {
 			fmt.Printf("  Warning: %v\n", err)
 			continue
 		}
@@ -94,7 +304,7 @@ func (m *Manager) Down(ctx context.Context, count int, all bool) error {
 
 // Scale adjusts to exactly `target` runners.
 func (m *Manager) Scale(ctx context.Context, target int) error {
-	existing, err := m.Docker.ListManagedContainers(ctx)
+	existing, err := m.containers(ctx, false)
 	if err != nil {
 		return err
 	}
@@ -117,34 +327,58 @@ func (m *Manager) Scale(ctx context.Context, target int) error {
 	return m.Down(ctx, diff, false)
 }
 
-// List returns info about all managed runners.
+// List returns info about all managed runners. In fleet mode, hosts that
+// cannot be reached are reported on stderr and left out.
 func (m *Manager) List(ctx context.Context) ([]RunnerInfo, error) {
-	containers, err := m.Docker.ListManagedContainers(ctx)
+	containers, err := m.containers(ctx, true)
 	if err != nil {
 		return nil, err
 	}
 
 	var infos []RunnerInfo
 	for _, c := range containers {
-		infos = append(infos, RunnerInfo{
+		info := RunnerInfo{
 			Num:          c.Num,
 			Name:         c.Name,
+			Host:         c.Host,
 			ContainerID:  c.ID,
 			DockerState:  c.State,
 			DockerStatus: c.Status,
-		})
+		}
+		if len(m.Config.Repos) > 0 && c.Labels[docker.LabelRepoName] != "" {
+			info.Repo = c.Labels[docker.LabelRepoOwner] + "/" + c.Labels[docker.LabelRepoName]
+		}
+		infos = append(infos, info)
 	}
 	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Repo != infos[j].Repo {
+			return infos[i].Repo < infos[j].Repo
+		}
 		return infos[i].Num < infos[j].Num
 	})
 	return infos, nil
 }
 
+// PruneRunnerLabels drops the `ghr label` records of the config's runners
+// whose containers are gone and reports whether any were dropped. Nothing
+// is dropped if a host cannot be reached, as its containers may still exist.
+func (m *Manager) PruneRunnerLabels(ctx context.Context, rl *config.RunnerLabels) bool {
+	all, err := m.allContainers(ctx, false)
+	if err != nil {
+		return false
+	}
+	live := make(map[string]bool, len(all))
+	for _, c := range all {
+		live[c.ID] = true
+	}
+	return rl.Prune(m.root, live)
+}
+
 // Stop stops runners. If nameOrNum is empty and all is true, stops all.
 func (m *Manager) Stop(ctx context.Context, nameOrNum string, all bool) error {
 	if all {
 		return m.forEachManaged(ctx, "Stopping", func(ctx context.Context, c docker.RunnerContainer) error {
-			return m.Docker.StopRunner(ctx, c.Name)
+			return m.Client(c.Host).StopRunner(ctx, c.Name)
 		})
 	}
 	c, err := m.findRunner(ctx, nameOrNum)
@@ -152,14 +386,14 @@ func (m *Manager) Stop(ctx context.Context, nameOrNum string, all bool) error {
 		return err
 	}
 	fmt.Printf("Stopping %s...\n", c.Name)
-	return m.Docker.StopRunner(ctx, c.Name)
+	return m.Client(c.Host).StopRunner(ctx, c.Name)
 }
 
 // Start starts stopped runners. If nameOrNum is empty and all is true, starts all.
 func (m *Manager) Start(ctx context.Context, nameOrNum string, all bool) error {
 	if all {
 		return m.forEachManaged(ctx, "Starting", func(ctx context.Context, c docker.RunnerContainer) error {
-			return m.Docker.StartRunner(ctx, c.Name)
+			return m.Client(c.Host).StartRunner(ctx, c.Name)
 		})
 	}
 	c, err := m.findRunner(ctx, nameOrNum)
@@ -167,14 +401,14 @@ func (m *Manager) Start(ctx context.Context, nameOrNum string, all bool) error {
 		return err
 	}
 	fmt.Printf("Starting %s...\n", c.Name)
-	return m.Docker.StartRunner(ctx, c.Name)
+	return m.Client(c.Host).StartRunner(ctx, c.Name)
 }
 
 // Remove removes stopped runners. If nameOrNum is empty and all is true, removes all.
 func (m *Manager) Remove(ctx context.Context, nameOrNum string, all bool) error {
 	if all {
 		return m.forEachManaged(ctx, "Removing", func(ctx context.Context, c docker.RunnerContainer) error {
-			return m.Docker.RemoveRunner(ctx, c.Name)
+			return m.Client(c.Host).RemoveRunner(ctx, c.Name)
 		})
 	}
 	c, err := m.findRunner(ctx, nameOrNum)
@@ -182,29 +416,32 @@ func (m *Manager) Remove(ctx context.Context, nameOrNum string, all bool) error
 		return err
 	}
 	fmt.Printf("Removing %s...\n", c.Name)
-	return m.Docker.RemoveRunner(ctx, c.Name)
+	return m.Client(c.Host).RemoveRunner(ctx, c.Name)
 }
 
 func (m *Manager) findRunner(ctx context.Context, nameOrNum string) (docker.RunnerContainer, error) {
-	existing, err := m.Docker.ListManagedContainers(ctx)
+	existing, err := m.containers(ctx, false)
 	if err != nil {
 		return docker.RunnerContainer{}, err
 	}
 
-	prefix := m.Config.Runners.NamePrefix
-	for _, c := range existing {
-		if c.Name == nameOrNum ||
-			c.Name == prefix+"-runner-"+nameOrNum ||
-			fmt.Sprintf("%d", c.Num) == nameOrNum ||
-			strings.HasPrefix(c.ID, nameOrNum) {
-			return c, nil
-		}
+	// Names and numbers win over ID prefixes, so "3" is runner 3 even when
+	// another container's ID starts with 3.
+	matched := matchRef(existing, nameOrNum, m.Config.Runners.NamePrefix)
+	if len(matched) == 0 {
+		return docker.RunnerContainer{}, fmt.Errorf("runner %q not found", nameOrNum)
+	}
+	if err := checkAmbiguous(nameOrNum, matched); err != nil {
+		return docker.RunnerContainer{}, err
+	}
+	if len(matched) > 1 {
+		return docker.RunnerContainer{}, fmt.Errorf("%q matches %d runners; give one runner", nameOrNum, len(matched))
 	}
-	return docker.RunnerContainer{}, fmt.Errorf("runner %q not found", nameOrNum)
+	return matched[0], nil
 }
 
 func (m *Manager) forEachManaged(ctx context.Context, action string, fn func(context.Context, docker.RunnerContainer) error) error {
-	existing, err := m.Docker.ListManagedContainers(ctx)
+	existing, err := m.containers(ctx, false)
 	if err != nil {
 		return err
 	}
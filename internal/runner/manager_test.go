@@ -0,0 +1,735 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker/dockertest"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/github/githubtest"
+)
+
+var _ Runtime = (*dockertest.Fake)(nil)
+
+func testConfig() *config.Config {
+	cfg := config.Default()
+	cfg.Org = "myorg"
+	cfg.Token = "ghp_test"
+	return cfg
+}
+
+// newTestHost returns a fake host already running the given runner numbers,
+// indexed in that order.
+func newTestHost(nums ...int) *dockertest.Fake {
+	f := dockertest.New()
+	for i, n := range nums {
+		labels := docker.ManagedLabels(testConfig(), n)
+		labels[docker.LabelRunnerIndex] = fmt.Sprint(i)
+		f.Add(docker.RunnerContainer{Name: fmt.Sprintf("ghr-runner-%d", n), Num: n, Labels: labels})
+	}
+	return f
+}
+
+func newTestManager(nums ...int) (*Manager, *dockertest.Fake) {
+	f := newTestHost(nums...)
+	return NewManager(testConfig(), []*Host{{Runtime: f}}), f
+}
+
+// failOn returns a Fake.Err hook that fails op on target.
+func failOn(op, target string) func(string, string) error {
+	return func(o, t string) error {
+		if o == op && t == target {
+			return errors.New(op + " " + target + " failed")
+		}
+		return nil
+	}
+}
+
+func TestUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []int
+		count    int
+		wantNums []int
+	}{
+		{"empty host", nil, 3, []int{1, 2, 3}},
+		{"fills gaps first", []int{1, 3, 6}, 3, []int{1, 2, 3, 4, 5, 6}},
+		{"appends after the highest", []int{1, 2}, 1, []int{1, 2, 3}},
+		{"none", []int{1}, 0, []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, f := newTestManager(tt.existing...)
+			created, err := m.Up(context.Background(), tt.count)
+			if err != nil {
+				t.Fatalf("Up() error = %v", err)
+			}
+			if len(created) != tt.count {
+				t.Errorf("Up() returned %d IDs, want %d", len(created), tt.count)
+			}
+			if got := f.Nums(); !reflect.DeepEqual(got, tt.wantNums) {
+				t.Errorf("runners after Up() = %v, want %v", got, tt.wantNums)
+			}
+		})
+	}
+}
+
+func TestUpLabelsContainers(t *testing.T) {
+	m, f := newTestManager()
+	if _, err := m.Up(context.Background(), 1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	labels := f.Containers()[0].Labels
+	if labels[docker.LabelManaged] != "true" || labels[docker.LabelRunnerNum] != "1" {
+		t.Errorf("labels = %v, want managed runner 1", labels)
+	}
+	if labels[docker.LabelScope] != "org" || labels[docker.LabelOrg] != "myorg" {
+		t.Errorf("labels = %v, want org myorg", labels)
+	}
+
+	cfg := testConfig()
+	cfg.Scope, cfg.Org, cfg.Enterprise = "enterprise", "", "acme"
+	f = dockertest.New()
+	if _, err := NewManager(cfg, []*Host{{Runtime: f}}).Up(context.Background(), 1); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	labels = f.Containers()[0].Labels
+	if labels[docker.LabelScope] != "enterprise" || labels[docker.LabelEnterprise] != "acme" {
+		t.Errorf("labels = %v, want enterprise acme", labels)
+	}
+	if _, ok := labels[docker.LabelOrg]; ok {
+		t.Errorf("labels = %v, want no org label", labels)
+	}
+}
+
+func TestUpPartialFailure(t *testing.T) {
+	m, f := newTestManager(1)
+	f.Err = failOn("create", "ghr-runner-3")
+
+	created, err := m.Up(context.Background(), 3)
+	if err == nil || !strings.Contains(err.Error(), "creating runner 3") {
+		t.Fatalf("Up() error = %v, want a failure creating runner 3", err)
+	}
+	if len(created) != 1 || created[0] != f.Containers()[1].ID {
+		t.Errorf("Up() returned %v, want the ID of runner 2", created)
+	}
+	if got, want := f.Nums(), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runners after Up() = %v, want %v", got, want)
+	}
+}
+
+func TestUpErrors(t *testing.T) {
+	t.Run("unresolvable token", func(t *testing.T) {
+		m, f := newTestManager()
+		m.Config.Token = "env:GHR_TEST_UNSET_TOKEN"
+		if _, err := m.Up(context.Background(), 1); err == nil {
+			t.Fatal("Up() expected error for an unresolvable token")
+		}
+		if n := len(f.Containers()); n != 0 {
+			t.Errorf("Up() created %d containers, want 0", n)
+		}
+	})
+	t.Run("list fails", func(t *testing.T) {
+		m, f := newTestManager()
+		f.Err = failOn("list", "")
+		if _, err := m.Up(context.Background(), 1); err == nil {
+			t.Fatal("Up() expected error when listing fails")
+		}
+	})
+}
+
+func TestDown(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []int
+		count    int
+		all      bool
+		wantNums []int
+	}{
+		{"highest first", []int{1, 2, 3, 4, 5}, 2, false, []int{1, 2, 3}},
+		{"with gaps", []int{1, 4, 9}, 2, false, []int{1}},
+		{"more than running", []int{1, 2}, 5, false, nil},
+		{"all", []int{1, 2, 7}, 0, true, nil},
+		{"nothing running", nil, 3, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, f := newTestManager(tt.existing...)
+			if err := m.Down(context.Background(), tt.count, tt.all); err != nil {
+				t.Fatalf("Down() error = %v", err)
+			}
+			if got := f.Nums(); !reflect.DeepEqual(got, tt.wantNums) {
+				t.Errorf("runners after Down() = %v, want %v", got, tt.wantNums)
+			}
+		})
+	}
+}
+
+func TestDownPartialFailure(t *testing.T) {
+	m, f := newTestManager(1, 2, 3)
+	f.Err = failOn("remove", "ghr-runner-3")
+
+	// A runner that cannot be removed is reported and skipped.
+	if err := m.Down(context.Background(), 2, false); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if got, want := f.Nums(), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runners after Down() = %v, want %v", got, want)
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []int
+		target   int
+		wantNums []int
+	}{
+		{"up", []int{1, 3}, 4, []int{1, 2, 3, 4}},
+		{"down", []int{1, 2, 3, 5}, 2, []int{1, 2}},
+		{"unchanged", []int{2, 4}, 2, []int{2, 4}},
+		{"to zero", []int{1, 2}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, f := newTestManager(tt.existing...)
+			if err := m.Scale(context.Background(), tt.target); err != nil {
+				t.Fatalf("Scale() error = %v", err)
+			}
+			if got := f.Nums(); !reflect.DeepEqual(got, tt.wantNums) {
+				t.Errorf("runners after Scale() = %v, want %v", got, tt.wantNums)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	m, f := newTestManager(3, 1, 2)
+	if err := f.StopRunner(context.Background(), "ghr-runner-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := m.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var nums []int
+	for _, info := range infos {
+		nums = append(nums, info.Num)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(nums, want) {
+		t.Errorf("List() nums = %v, want %v", nums, want)
+	}
+	if infos[1].DockerState != "exited" || infos[0].DockerState != "running" {
+		t.Errorf("List() states = %q, %q, want running, exited", infos[0].DockerState, infos[1].DockerState)
+	}
+}
+
+func TestSharedHost(t *testing.T) {
+	ctx := context.Background()
+	f := dockertest.New()
+	orgCfg := testConfig()
+	repoCfg := testConfig()
+	repoCfg.Scope, repoCfg.Org = "repo", ""
+	repoCfg.Repo = config.RepoConfig{Owner: "me", Name: "app"}
+	repoCfg.Runners.NamePrefix = "app"
+	org := NewManager(orgCfg, []*Host{{Runtime: f}})
+	repo := NewManager(repoCfg, []*Host{{Runtime: f}})
+
+	if _, err := org.Up(ctx, 2); err != nil {
+		t.Fatalf("Up(org) error = %v", err)
+	}
+	if _, err := repo.Up(ctx, 3); err != nil {
+		t.Fatalf("Up(repo) error = %v", err)
+	}
+	if got := len(f.Containers()); got != 5 {
+		t.Fatalf("%d containers, want 5", got)
+	}
+
+	// Each config sees, scales and removes only its own runners.
+	if infos, err := org.List(ctx); err != nil || len(infos) != 2 {
+		t.Errorf("List(org) = %d runners, %v, want 2", len(infos), err)
+	}
+	if err := repo.Scale(ctx, 1); err != nil {
+		t.Fatalf("Scale(repo, 1) error = %v", err)
+	}
+	if err := org.Down(ctx, 0, true); err != nil {
+		t.Fatalf("Down(org, all) error = %v", err)
+	}
+	var names []string
+	for _, c := range f.Containers() {
+		names = append(names, c.Name)
+	}
+	if want := []string{"app-runner-1"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("containers = %v, want %v", names, want)
+	}
+
+	// Another org on the same host sees none of them.
+	other := testConfig()
+	other.Org = "otherorg"
+	if infos, err := NewManager(other, []*Host{{Runtime: f}}).List(ctx); err != nil || len(infos) != 0 {
+		t.Errorf("List(otherorg) = %d runners, %v, want none", len(infos), err)
+	}
+}
+
+func TestFindRunner(t *testing.T) {
+	m, f := newTestManager(1, 2, 12)
+	id := f.Containers()[1].ID
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"ghr-runner-12", "ghr-runner-12", false},
+		{"2", "ghr-runner-2", false},
+		{id, "ghr-runner-2", false},
+		{id[:6], "ghr-runner-2", false},
+		{"7", "", true},
+		{"other-runner-1", "", true},
+	}
+	for _, tt := range tests {
+		c, err := m.findRunner(context.Background(), tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("findRunner(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if c.Name != tt.want {
+			t.Errorf("findRunner(%q) = %q, want %q", tt.ref, c.Name, tt.want)
+		}
+	}
+}
+
+func TestStopStartRemove(t *testing.T) {
+	ctx := context.Background()
+	m, f := newTestManager(1, 2, 3)
+
+	if err := m.Stop(ctx, "2", false); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if got := f.Containers()[1].State; got != "exited" {
+		t.Errorf("runner 2 state after Stop() = %q, want exited", got)
+	}
+	if err := m.Start(ctx, "ghr-runner-2", false); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if got := f.Containers()[1].State; got != "running" {
+		t.Errorf("runner 2 state after Start() = %q, want running", got)
+	}
+	if err := m.Remove(ctx, "3", false); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if got, want := f.Nums(), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runners after Remove() = %v, want %v", got, want)
+	}
+	if err := m.Stop(ctx, "9", false); err == nil {
+		t.Error("Stop() expected error for a missing runner")
+	}
+}
+
+func TestAllContinuesPastFailures(t *testing.T) {
+	ctx := context.Background()
+	m, f := newTestManager(1, 2, 3)
+	f.Err = failOn("stop", "ghr-runner-2")
+
+	if err := m.Stop(ctx, "", true); err != nil {
+		t.Fatalf("Stop(all) error = %v", err)
+	}
+	var states []string
+	for _, c := range f.Containers() {
+		states = append(states, c.State)
+	}
+	if want := []string{"exited", "running", "exited"}; !reflect.DeepEqual(states, want) {
+		t.Errorf("states after Stop(all) = %v, want %v", states, want)
+	}
+
+	f.Err = nil
+	if err := m.Remove(ctx, "", true); err != nil {
+		t.Fatalf("Remove(all) error = %v", err)
+	}
+	if n := len(f.Containers()); n != 0 {
+		t.Errorf("%d runners left after Remove(all), want 0", n)
+	}
+	if err := m.Start(ctx, "", true); err != nil {
+		t.Errorf("Start(all) with no runners error = %v", err)
+	}
+}
+
+func newTestFleet(fakes ...*dockertest.Fake) *Manager {
+	cfg := testConfig()
+	var hosts []*Host
+	for i, f := range fakes {
+		name := fmt.Sprintf("host-%d", i+1)
+		cfg.Hosts = append(cfg.Hosts, config.HostConf{Name: name})
+		hosts = append(hosts, &Host{Name: name, Runtime: f})
+	}
+	return NewManager(cfg, hosts)
+}
+
+func TestFleet(t *testing.T) {
+	ctx := context.Background()
+	a, b := newTestHost(1), newTestHost()
+	m := newTestFleet(a, b)
+	m.Hosts[1].Weight = 2
+
+	// Numbers are unique across hosts; runners go where the load is lowest.
+	if _, err := m.Up(ctx, 5); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if got, want := a.Nums(), []int{1, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("host-1 runners = %v, want %v", got, want)
+	}
+	if got, want := b.Nums(), []int{2, 3, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("host-2 runners = %v, want %v", got, want)
+	}
+
+	// Removals come from the most loaded host, highest number first.
+	if err := m.Down(ctx, 3, false); err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if got, want := a.Nums(), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("host-1 runners after Down() = %v, want %v", got, want)
+	}
+	if got, want := b.Nums(), []int{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("host-2 runners after Down() = %v, want %v", got, want)
+	}
+
+	// Commands on one runner go to the host it lives on.
+	if err := m.Stop(ctx, "3", false); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if got := b.Containers()[1].State; got != "exited" {
+		t.Errorf("runner 3 state = %q, want exited", got)
+	}
+
+	m.Close()
+	if !a.Closed() || !b.Closed() {
+		t.Error("Close() did not close every host")
+	}
+}
+
+func TestFleetUnreachableHost(t *testing.T) {
+	ctx := context.Background()
+	a, b := newTestHost(1), newTestHost(2)
+	b.Err = failOn("list", "")
+	m := newTestFleet(a, b)
+
+	infos, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(infos) != 1 || infos[0].Host != "host-1" {
+		t.Errorf("List() = %+v, want only runner 1 on host-1", infos)
+	}
+
+	// Numbering and placement need every host, so changes fail.
+	if _, err := m.Up(ctx, 1); err == nil || !strings.Contains(err.Error(), "host host-2") {
+		t.Errorf("Up() error = %v, want a host-2 failure", err)
+	}
+	if n := len(a.Containers()); n != 1 {
+		t.Errorf("host-1 has %d runners after a failed Up(), want 1", n)
+	}
+}
+
+func TestPools(t *testing.T) {
+	ctx := context.Background()
+	cfg := testConfig()
+	cfg.Scope, cfg.Org = "repo", ""
+	cfg.Repos = []config.RepoPoolConf{
+		{Owner: "me", Name: "app", Count: 2},
+		{Owner: "me", Name: "web", Count: 1, Labels: []string{"web"}},
+	}
+	f := dockertest.New()
+	m := NewManager(cfg, []*Host{{Runtime: f}})
+
+	pools, err := m.Pools()
+	if err != nil || len(pools) != 2 {
+		t.Fatalf("Pools() = %v, %v, want two pools", pools, err)
+	}
+	for _, p := range pools {
+		if _, err := p.Up(ctx, p.Config.Runners.Count); err != nil {
+			t.Fatalf("Up(%s) error = %v", p.Config.Repo.FullName(), err)
+		}
+	}
+	var names []string
+	for _, c := range f.Containers() {
+		names = append(names, c.Name)
+	}
+	if want := []string{"ghr-app-runner-1", "ghr-web-runner-1", "ghr-app-runner-2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("containers = %v, want %v", names, want)
+	}
+
+	// Each pool sees only its own runners.
+	if err := pools[1].Scale(ctx, 0); err != nil {
+		t.Fatalf("Scale(web, 0) error = %v", err)
+	}
+	if got := len(f.Containers()); got != 2 {
+		t.Errorf("%d containers after scaling web to 0, want the 2 app runners", got)
+	}
+	if _, err := pools[1].Up(ctx, 1); err != nil {
+		t.Fatalf("Up(web) error = %v", err)
+	}
+
+	infos, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var repos []string
+	for _, r := range infos {
+		repos = append(repos, r.Repo)
+	}
+	if want := []string{"me/app", "me/app", "me/web"}; !reflect.DeepEqual(repos, want) {
+		t.Errorf("List() repos = %v, want runners grouped by repo", repos)
+	}
+
+	// Runner numbers restart in each repo, so a bare number is ambiguous
+	// unless a pool is selected.
+	var ambiguous *AmbiguousRefError
+	if err := m.Stop(ctx, "1", false); !errors.As(err, &ambiguous) {
+		t.Errorf("Stop(1) error = %v, want an ambiguous ref", err)
+	} else if want := []string{"ghr-app-runner-1", "ghr-web-runner-1"}; !reflect.DeepEqual(ambiguous.Names, want) {
+		t.Errorf("ambiguous runners = %v, want %v", ambiguous.Names, want)
+	}
+	if err := pools[1].Stop(ctx, "1", false); err != nil {
+		t.Errorf("Stop(web, 1) error = %v", err)
+	}
+	if err := m.Stop(ctx, "ghr-app-runner-2", false); err != nil {
+		t.Errorf("Stop(ghr-app-runner-2) error = %v", err)
+	}
+
+	if only, err := m.Pools("ME/web"); err != nil || len(only) != 1 || only[0].Config.Repo.Name != "web" {
+		t.Errorf("Pools(ME/web) = %v, %v, want the web pool", only, err)
+	}
+	if _, err := m.Pools("me/other"); err == nil {
+		t.Error("Pools(me/other) expected error")
+	}
+	if _, err := NewManager(testConfig(), m.Hosts).Pools("me/app"); err == nil {
+		t.Error("Pools(me/app) without repos expected error")
+	}
+}
+
+func TestListWithGitHubStatus(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(1, 2, 3)
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	idle := srv.AddRunner("myorg", "ghr-runner-1", "online", false)
+	busy := srv.AddRunner("myorg", "ghr-runner-2", "online", true)
+	srv.AddRunner("myorg", "other-runner", "offline", false)
+
+	infos, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	ghc, err := ghclient.NewClient(ctx, "t", srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := ghc.ListOrgRunners(ctx, "myorg")
+	if err != nil {
+		t.Fatalf("ListOrgRunners() error = %v", err)
+	}
+	MergeGitHubStatus(infos, statuses)
+
+	want := []struct {
+		id     int64
+		status string
+		busy   bool
+	}{{idle, "online", false}, {busy, "online", true}, {0, "", false}}
+	for i, w := range want {
+		got := infos[i]
+		if got.GitHubID != w.id || got.GitHubStatus != w.status || got.Busy != w.busy {
+			t.Errorf("runner %d GitHub fields = %d, %q, %v, want %d, %q, %v",
+				got.Num, got.GitHubID, got.GitHubStatus, got.Busy, w.id, w.status, w.busy)
+		}
+	}
+}
+
+func TestApplyRunnerLabels(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(1, 2, 3)
+	infos, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	rl := &config.RunnerLabels{Runners: map[string]config.RunnerLabelsEntry{
+		infos[0].ContainerID: {Name: "ghr-runner-1", Labels: []string{"team-ml"}},
+		infos[1].ContainerID: {Name: "ghr-runner-2", Labels: []string{}},
+		"f00000000000":       {Name: "ghr-runner-3", Labels: []string{"stale"}},
+	}}
+	ApplyRunnerLabels(infos, rl)
+
+	if !infos[0].Relabeled || !reflect.DeepEqual(infos[0].Labels, []string{"team-ml"}) {
+		t.Errorf("runner 1 = %v, %v, want relabeled [team-ml]", infos[0].Relabeled, infos[0].Labels)
+	}
+	if !infos[1].Relabeled || len(infos[1].Labels) != 0 {
+		t.Errorf("runner 2 = %v, %v, want relabeled with no labels", infos[1].Relabeled, infos[1].Labels)
+	}
+	if infos[2].Relabeled {
+		t.Errorf("runner 3 picked up the record of another container: %v", infos[2].Labels)
+	}
+}
+
+func TestRunnerLabelsDrift(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(1, 2, 3)
+	srv := githubtest.NewServer(t)
+	srv.AddOrg("myorg")
+	srv.AddRunner("myorg", "ghr-runner-1", "online", false, "self-hosted", "team-ml")
+	srv.AddRunner("myorg", "ghr-runner-2", "online", false, "self-hosted", "gpu")
+	srv.AddRunner("myorg", "ghr-runner-3", "online", false, "self-hosted", "linux")
+
+	infos, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	rl := &config.RunnerLabels{Runners: map[string]config.RunnerLabelsEntry{
+		infos[0].ContainerID: {Name: "ghr-runner-1", Labels: []string{"Team-ML"}},
+		infos[1].ContainerID: {Name: "ghr-runner-2", Labels: []string{"team-ml"}},
+	}}
+	ApplyRunnerLabels(infos, rl)
+	ghc, err := ghclient.NewClient(ctx, "t", srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := ghc.ListOrgRunners(ctx, "myorg")
+	if err != nil {
+		t.Fatalf("ListOrgRunners() error = %v", err)
+	}
+	MergeGitHubStatus(infos, statuses)
+
+	if infos[0].LabelsDrift {
+		t.Errorf("runner 1 drift = %v, want labels matching GitHub's", infos[0].GitHubLabels)
+	}
+	if !infos[1].LabelsDrift || !reflect.DeepEqual(infos[1].GitHubLabels, []string{"gpu"}) {
+		t.Errorf("runner 2 = %v, %v, want drift to [gpu]", infos[1].LabelsDrift, infos[1].GitHubLabels)
+	}
+	// Runners never relabeled keep their configured labels unchecked.
+	if infos[2].LabelsDrift {
+		t.Errorf("runner 3 drift = %v, want none without a record", infos[2].GitHubLabels)
+	}
+}
+
+func TestPruneRunnerLabels(t *testing.T) {
+	ctx := context.Background()
+	a, b := newTestHost(1), newTestHost(2)
+	m := newTestFleet(a, b)
+	infos, err := m.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	entry := func(name, target string) config.RunnerLabelsEntry {
+		return config.RunnerLabelsEntry{Name: name, Scope: "org", Target: target, Labels: []string{"team-ml"}}
+	}
+	rl := &config.RunnerLabels{Runners: map[string]config.RunnerLabelsEntry{
+		infos[0].ContainerID: entry("ghr-runner-1", "myorg"),
+		infos[1].ContainerID: entry("ghr-runner-2", "myorg"),
+		"f00000000000":       entry("ghr-runner-3", "myorg"),
+		"f00000000001":       entry("ghr-runner-1", "otherorg"),
+	}}
+
+	// Runner 2's host is down, so its container may still exist.
+	b.Err = failOn("list", "")
+	if m.PruneRunnerLabels(ctx, rl) || len(rl.Runners) != 4 {
+		t.Errorf("PruneRunnerLabels() with a host down dropped records: %v", rl.Runners)
+	}
+
+	b.Err = nil
+	if err := m.Remove(ctx, "2", false); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if !m.PruneRunnerLabels(ctx, rl) {
+		t.Error("PruneRunnerLabels() = false, want true")
+	}
+	want := map[string]config.RunnerLabelsEntry{
+		infos[0].ContainerID: entry("ghr-runner-1", "myorg"),
+		"f00000000001":       entry("ghr-runner-1", "otherorg"),
+	}
+	if !reflect.DeepEqual(rl.Runners, want) {
+		t.Errorf("records after PruneRunnerLabels() = %v, want %v", rl.Runners, want)
+	}
+}
+
+func TestUpRendersTemplates(t *testing.T) {
+	ctx := context.Background()
+	a, b := newTestHost(1), newTestHost()
+	m := newTestFleet(a, b)
+	m.Config.Runners.NamePrefix = "ci-{{.Host}}"
+	m.Config.Runners.Labels = []string{"linux", `{{if eq (mod .Num 2) 1}}arm-emulated{{end}}`}
+	m.Config.Runners.ExtraEnv = map[string]string{"SLOT": "{{.Host}}/{{.Index}}"}
+
+	plan, err := m.Plan(ctx, 3)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := []struct {
+		name, host, slot string
+		labels           []string
+	}{
+		{"ci-host-2-runner-2", "host-2", "host-2/0", []string{"linux"}},
+		{"ci-host-1-runner-3", "host-1", "host-1/1", []string{"linux", "arm-emulated"}},
+		{"ci-host-2-runner-4", "host-2", "host-2/1", []string{"linux"}},
+	}
+	if len(plan) != len(want) {
+		t.Fatalf("Plan() = %d runners, want %d", len(plan), len(want))
+	}
+	for i, w := range want {
+		p := plan[i]
+		if p.Name != w.name || p.Host != w.host || p.Config.Runners.ExtraEnv["SLOT"] != w.slot || !reflect.DeepEqual(p.Config.Runners.Labels, w.labels) {
+			t.Errorf("plan[%d] = %s on %s, SLOT=%s, labels %v; want %s on %s, SLOT=%s, labels %v",
+				i, p.Name, p.Host, p.Config.Runners.ExtraEnv["SLOT"], p.Config.Runners.Labels, w.name, w.host, w.slot, w.labels)
+		}
+	}
+	if len(a.Containers())+len(b.Containers()) != 1 {
+		t.Error("Plan() created containers")
+	}
+
+	if _, err := m.Up(ctx, 3); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	var names []string
+	for _, c := range b.Containers() {
+		names = append(names, c.Name)
+	}
+	if want := []string{"ci-host-2-runner-2", "ci-host-2-runner-4"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("host-2 containers = %v, want %v", names, want)
+	}
+}
+
+func TestUpReusesIndexes(t *testing.T) {
+	ctx := context.Background()
+	m, f := newTestManager()
+	m.Config.Runners.ExtraEnv = map[string]string{"SLOT": "{{.Index}}"}
+	if _, err := m.Up(ctx, 3); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	// Scale down by removing the runner with index 0, then back up.
+	if err := m.Remove(ctx, "1", false); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	plan, err := m.Plan(ctx, 1)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if p := plan[0]; p.Num != 1 || p.Index != 0 || p.Config.Runners.ExtraEnv["SLOT"] != "0" {
+		t.Errorf("Plan() = runner %d with index %d, SLOT=%s; want runner 1 with index 0", p.Num, p.Index, p.Config.Runners.ExtraEnv["SLOT"])
+	}
+	if err := m.Scale(ctx, 3); err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+
+	indexes := make(map[string]string)
+	for _, c := range f.Containers() {
+		indexes[c.Name] = c.Labels[docker.LabelRunnerIndex]
+	}
+	want := map[string]string{"ghr-runner-1": "0", "ghr-runner-2": "1", "ghr-runner-3": "2"}
+	if !reflect.DeepEqual(indexes, want) {
+		t.Errorf("runner indexes = %v, want %v", indexes, want)
+	}
+}
@@ -21,6 +21,20 @@ func NextNumbers(existing []int, count int) []int {
 	return result
 }
 
+// NextIndex returns the lowest {{.Index}}, starting from 0, that is not in
+// used.
+func NextIndex(used []int) int {
+	taken := make(map[int]bool, len(used))
+	for _, i := range used {
+		taken[i] = true
+	}
+	i := 0
+	for taken[i] {
+		i++
+	}
+	return i
+}
+
 // HighestNumbers returns the highest `count` numbers from the given slice,
 // sorted descending. Used by `down` to remove highest-numbered runners first.
 func HighestNumbers(existing []int, count int) []int {
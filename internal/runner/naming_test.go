@@ -58,3 +58,20 @@ func TestHighestNumbers(t *testing.T) {
 		})
 	}
 }
+
+func TestNextIndex(t *testing.T) {
+	tests := []struct {
+		used []int
+		want int
+	}{
+		{nil, 0},
+		{[]int{0, 1}, 2},
+		{[]int{1, 2}, 0},
+		{[]int{2, 0}, 1},
+	}
+	for _, tt := range tests {
+		if got := NextIndex(tt.used); got != tt.want {
+			t.Errorf("NextIndex(%v) = %d, want %d", tt.used, got, tt.want)
+		}
+	}
+}
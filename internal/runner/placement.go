@@ -0,0 +1,77 @@
+package runner
+
+import "fmt"
+
+// Load is the number of runners on a host and how many it should take.
+// Weight is the host's share relative to the other hosts (0 counts as 1);
+// Max caps the runners on the host (0 means no limit).
+type Load struct {
+	Count  int
+	Weight int
+	Max    int
+}
+
+func (l Load) weight() int {
+	if l.Weight <= 0 {
+		return 1
+	}
+	return l.Weight
+}
+
+// lighter reports whether a holds fewer runners than b relative to their
+// weights.
+func lighter(a, b Load) bool {
+	return a.Count*b.weight() < b.Count*a.weight()
+}
+
+// Place picks a host for each of n new runners, returning host indexes in
+// loads. Each runner goes to the host that is least loaded relative to its
+// weight and below its Max; ties go to the earlier host. It fails if the
+// hosts cannot take n more runners.
+func Place(loads []Load, n int) ([]int, error) {
+	loads = append([]Load(nil), loads...)
+	placed := make([]int, 0, n)
+	for len(placed) < n {
+		best := -1
+		for i, l := range loads {
+			if l.Max > 0 && l.Count >= l.Max {
+				continue
+			}
+			if best < 0 || lighter(l, loads[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			return nil, fmt.Errorf("hosts can take only %d more runner(s) within their max_runners", len(placed))
+		}
+		loads[best].Count++
+		placed = append(placed, best)
+	}
+	return placed, nil
+}
+
+// PickRemovals picks the hosts to remove n runners from, one index per
+// runner, taking each from the host that is most loaded relative to its
+// weight. Ties go to the earlier host. Hosts without runners are skipped, so
+// fewer than n indexes are returned if the hosts run fewer than n runners.
+func PickRemovals(loads []Load, n int) []int {
+	loads = append([]Load(nil), loads...)
+	var picked []int
+	for len(picked) < n {
+		best := -1
+		for i, l := range loads {
+			if l.Count == 0 {
+				continue
+			}
+			if best < 0 || lighter(loads[best], l) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		loads[best].Count--
+		picked = append(picked, best)
+	}
+	return picked
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlace(t *testing.T) {
+	tests := []struct {
+		name    string
+		loads   []Load
+		n       int
+		want    []int
+		wantErr bool
+	}{
+		{"single host", []Load{{}}, 3, []int{0, 0, 0}, false},
+		{"even spread", []Load{{}, {}, {}}, 5, []int{0, 1, 2, 0, 1}, false},
+		{"fills the emptier host first", []Load{{Count: 3}, {Count: 1}}, 3, []int{1, 1, 0}, false},
+		{"by weight", []Load{{Weight: 2}, {Weight: 1}}, 6, []int{0, 1, 0, 0, 1, 0}, false},
+		{"max runners", []Load{{Max: 1}, {}}, 3, []int{0, 1, 1}, false},
+		{"full", []Load{{Count: 2, Max: 2}, {Count: 1, Max: 2}}, 2, nil, true},
+		{"none", []Load{{}}, 0, []int{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Place(tt.loads, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Place() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Place(%v, %d) = %v, want %v", tt.loads, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickRemovals(t *testing.T) {
+	tests := []struct {
+		name  string
+		loads []Load
+		n     int
+		want  []int
+	}{
+		{"most loaded first", []Load{{Count: 1}, {Count: 3}}, 2, []int{1, 1}},
+		{"evens out", []Load{{Count: 2}, {Count: 3}}, 3, []int{1, 0, 1}},
+		{"by weight", []Load{{Count: 4, Weight: 2}, {Count: 3}}, 2, []int{1, 0}},
+		{"more than running", []Load{{Count: 1}, {}}, 3, []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PickRemovals(tt.loads, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PickRemovals(%v, %d) = %v, want %v", tt.loads, tt.n, got, tt.want)
+			}
+		})
+	}
+}
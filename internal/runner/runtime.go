@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+)
+
+// Runtime is the container backend a host's runners live on. *docker.Client
+// implements it for Docker and Podman; dockertest.Fake is an in-memory
+// implementation for tests.
+type Runtime interface {
+	// CreateRunner creates and starts runner num, with the given
+	// {{.Index}}, and returns its container ID.
+	CreateRunner(ctx context.Context, cfg *config.Config, num, index int, token string) (string, error)
+	// ListManagedContainers returns the ghr-managed containers of cfg's
+	// scope and target, including stopped ones.
+	ListManagedContainers(ctx context.Context, cfg *config.Config) ([]docker.RunnerContainer, error)
+	StartRunner(ctx context.Context, idOrName string) error
+	StopRunner(ctx context.Context, idOrName string) error
+	// RemoveRunner removes a container, stopping it first if it is running.
+	RemoveRunner(ctx context.Context, idOrName string) error
+	InspectRunner(ctx context.Context, idOrName string) (types.ContainerJSON, error)
+	ContainerLogs(ctx context.Context, containerID string, opts docker.LogOptions) (io.ReadCloser, error)
+	// ContainerTTY reports whether the log stream is raw rather than
+	// multiplexed.
+	ContainerTTY(ctx context.Context, containerID string) (bool, error)
+	PullImage(ctx context.Context, ref string) error
+	Close() error
+}
+
+var _ Runtime = (*docker.Client)(nil)
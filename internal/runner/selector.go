@@ -0,0 +1,186 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+)
+
+// Selector picks a subset of managed runners. Refs may be runner names,
+// numbers, number ranges ("2-5") or container ID prefixes. Labels further
+// restrict the match to containers carrying every given Docker label.
+type Selector struct {
+	All    bool
+	Refs   []string
+	Labels map[string]string
+}
+
+// Empty reports whether the selector would match nothing explicitly.
+func (s Selector) Empty() bool {
+	return !s.All && len(s.Refs) == 0 && len(s.Labels) == 0
+}
+
+// ParseLabelSelectors parses KEY=VALUE pairs into a label map.
+func ParseLabelSelectors(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid label selector %q; expected KEY=VALUE", p)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// Match returns the containers matched by the selector, ordered by runner number.
+func (s Selector) Match(containers []docker.RunnerContainer, prefix string) ([]docker.RunnerContainer, error) {
+	var candidates []docker.RunnerContainer
+	if s.All || len(s.Refs) == 0 {
+		candidates = containers
+	} else {
+		seen := make(map[string]bool)
+		for _, ref := range s.Refs {
+			matched := matchRef(containers, ref, prefix)
+			if len(matched) == 0 {
+				return nil, fmt.Errorf("runner %q not found", ref)
+			}
+			if err := checkAmbiguous(ref, matched); err != nil {
+				return nil, err
+			}
+			for _, c := range matched {
+				if !seen[c.ID] {
+					seen[c.ID] = true
+					candidates = append(candidates, c)
+				}
+			}
+		}
+	}
+
+	var result []docker.RunnerContainer
+	for _, c := range candidates {
+		if hasLabels(c, s.Labels) {
+			result = append(result, c)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Num < result[j].Num
+	})
+	return result, nil
+}
+
+// Select lists managed containers and applies the selector to them.
+func (m *Manager) Select(ctx context.Context, sel Selector) ([]docker.RunnerContainer, error) {
+	existing, err := m.containers(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return sel.Match(existing, m.Config.Runners.NamePrefix)
+}
+
+// SplitRefs splits args of the form RUNNER... WORD... at the first arg that
+// is not a runner name, number or range of containers. Container ID prefixes
+// are not considered, since they cannot be told apart from other words.
+func SplitRefs(containers []docker.RunnerContainer, args []string, prefix string) (refs, rest []string) {
+	for i, a := range args {
+		if len(matchName(containers, a, prefix)) == 0 {
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
+
+// matchRef resolves a single ref. Container ID prefixes are only consulted
+// when no name, number or range matches, so "1" never picks up an ID that
+// happens to start with 1.
+func matchRef(containers []docker.RunnerContainer, ref, prefix string) []docker.RunnerContainer {
+	matched := matchName(containers, ref, prefix)
+	if len(matched) > 0 {
+		return matched
+	}
+	for _, c := range containers {
+		if strings.HasPrefix(c.ID, ref) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// matchName resolves a runner name, number or number range. prefix may be
+// a template, so a number is also matched against the names it yields.
+func matchName(containers []docker.RunnerContainer, ref, prefix string) []docker.RunnerContainer {
+	lo, hi, isRange := parseRange(ref)
+	pattern := config.NamePattern(prefix)
+	var matched []docker.RunnerContainer
+	for _, c := range containers {
+		if c.Name == ref ||
+			(pattern.MatchString(c.Name) && strings.HasSuffix(c.Name, "-runner-"+ref)) ||
+			strconv.Itoa(c.Num) == ref ||
+			(isRange && c.Num >= lo && c.Num <= hi) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// AmbiguousRefError is returned for a runner number or range that matches
+// the runners of several repos in repos:, since each repo numbers its
+// runners from 1.
+type AmbiguousRefError struct {
+	Ref   string
+	Names []string // the matched runners
+}
+
+func (e *AmbiguousRefError) Error() string {
+	return fmt.Sprintf("runner %q matches runners of several repos: %s; give a runner name or --repo", e.Ref, strings.Join(e.Names, ", "))
+}
+
+// checkAmbiguous returns an *AmbiguousRefError if matched holds the runners
+// of more than one repo.
+func checkAmbiguous(ref string, matched []docker.RunnerContainer) error {
+	repos := make(map[string]bool)
+	var names []string
+	for _, c := range matched {
+		repos[c.Labels[docker.LabelRepoOwner]+"/"+c.Labels[docker.LabelRepoName]] = true
+		names = append(names, c.Name)
+	}
+	if len(repos) < 2 {
+		return nil
+	}
+	sort.Strings(names)
+	return &AmbiguousRefError{Ref: ref, Names: names}
+}
+
+// parseRange parses an inclusive "LO-HI" runner number range.
+func parseRange(ref string) (int, int, bool) {
+	a, b, ok := strings.Cut(ref, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	lo, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, 0, false
+	}
+	hi, err := strconv.Atoi(b)
+	if err != nil || hi < lo {
+		return 0, 0, false
+	}
+	return lo, hi, true
+}
+
+func hasLabels(c docker.RunnerContainer, labels map[string]string) bool {
+	for k, v := range labels {
+		if c.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
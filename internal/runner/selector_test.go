@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/docker"
+)
+
+func TestSelectorMatch(t *testing.T) {
+	containers := []docker.RunnerContainer{
+		{ID: "1aaaaaaaaaaa", Name: "ghr-runner-3", Num: 3, Labels: map[string]string{"team": "a"}},
+		{ID: "bbbbbbbbbbbb", Name: "ghr-runner-1", Num: 1, Labels: map[string]string{"team": "b"}},
+		{ID: "cccccccccccc", Name: "ghr-runner-2", Num: 2, Labels: map[string]string{"team": "a"}},
+		{ID: "dddddddddddd", Name: "ghr-runner-5", Num: 5},
+	}
+
+	tests := []struct {
+		name    string
+		sel     Selector
+		want    []int
+		wantErr bool
+	}{
+		{"all", Selector{All: true}, []int{1, 2, 3, 5}, false},
+		{"by number", Selector{Refs: []string{"1"}}, []int{1}, false},
+		{"by name", Selector{Refs: []string{"ghr-runner-2"}}, []int{2}, false},
+		{"partial name", Selector{Refs: []string{"runner-5"}}, nil, true},
+		{"by range", Selector{Refs: []string{"2-4"}}, []int{2, 3}, false},
+		{"by id prefix", Selector{Refs: []string{"ddd"}}, []int{5}, false},
+		{"duplicates collapse", Selector{Refs: []string{"2", "1-3"}}, []int{1, 2, 3}, false},
+		{"labels only", Selector{Labels: map[string]string{"team": "a"}}, []int{2, 3}, false},
+		{"refs and labels", Selector{Refs: []string{"1-2"}, Labels: map[string]string{"team": "a"}}, []int{2}, false},
+		{"unknown ref", Selector{Refs: []string{"9"}}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sel.Match(containers, "ghr")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Match() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var nums []int
+			for _, c := range got {
+				nums = append(nums, c.Num)
+			}
+			if !reflect.DeepEqual(nums, tt.want) {
+				t.Errorf("Match() = %v, want %v", nums, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectorMatchRepos(t *testing.T) {
+	repo := func(name string) map[string]string {
+		return map[string]string{docker.LabelRepoOwner: "me", docker.LabelRepoName: name}
+	}
+	containers := []docker.RunnerContainer{
+		{ID: "aaaaaaaaaaaa", Name: "ghr-app-runner-1", Num: 1, Labels: repo("app")},
+		{ID: "bbbbbbbbbbbb", Name: "ghr-app-runner-2", Num: 2, Labels: repo("app")},
+		{ID: "cccccccccccc", Name: "ghr-web-runner-1", Num: 1, Labels: repo("web")},
+	}
+
+	var ambiguous *AmbiguousRefError
+	for _, ref := range []string{"1", "1-2"} {
+		if _, err := (Selector{Refs: []string{ref}}).Match(containers, "ghr"); !errors.As(err, &ambiguous) {
+			t.Errorf("Match(%s) error = %v, want an ambiguous ref", ref, err)
+		}
+	}
+	for _, ref := range []string{"2", "ghr-web-runner-1", "ccc"} {
+		if got, err := (Selector{Refs: []string{ref}}).Match(containers, "ghr"); err != nil || len(got) != 1 {
+			t.Errorf("Match(%s) = %v, %v, want one runner", ref, got, err)
+		}
+	}
+}
+
+func TestParseLabelSelectors(t *testing.T) {
+	got, err := ParseLabelSelectors([]string{"a=1", "b="})
+	if err != nil {
+		t.Fatalf("ParseLabelSelectors() error = %v", err)
+	}
+	want := map[string]string{"a": "1", "b": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLabelSelectors() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseLabelSelectors([]string{"novalue"}); err == nil {
+		t.Error("ParseLabelSelectors() expected error for missing '='")
+	}
+}
+
+func TestSelectorMatchTemplatePrefix(t *testing.T) {
+	// A container without a runner-num label is found through its name,
+	// which must fit the templated prefix.
+	containers := []docker.RunnerContainer{
+		{ID: "aaaaaaaaaaaa", Name: "ci-host-1-runner-4"},
+		{ID: "bbbbbbbbbbbb", Name: "other-runner-4"},
+	}
+	got, err := Selector{Refs: []string{"4"}}.Match(containers, "ci-{{.Host}}")
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "ci-host-1-runner-4" {
+		t.Errorf("Match() = %v, want ci-host-1-runner-4", got)
+	}
+}
+
+func TestSplitRefs(t *testing.T) {
+	containers := []docker.RunnerContainer{
+		{ID: "abcdef000000", Name: "ghr-runner-1", Num: 1},
+		{ID: "cafe00000000", Name: "ghr-runner-2", Num: 2},
+	}
+
+	tests := []struct {
+		args       []string
+		refs, rest []string
+	}{
+		{[]string{"1", "ghr-runner-2", "gpu", "team-ml"}, []string{"1", "ghr-runner-2"}, []string{"gpu", "team-ml"}},
+		{[]string{"1-2", "cafe"}, []string{"1-2"}, []string{"cafe"}},
+		{[]string{"gpu", "1"}, []string{}, []string{"gpu", "1"}},
+		{[]string{"2"}, []string{"2"}, nil},
+	}
+	for _, tt := range tests {
+		refs, rest := SplitRefs(containers, tt.args, "ghr")
+		if !reflect.DeepEqual(refs, tt.refs) || !reflect.DeepEqual(rest, tt.rest) {
+			t.Errorf("SplitRefs(%v) = %v, %v, want %v, %v", tt.args, refs, rest, tt.refs, tt.rest)
+		}
+	}
+}
@@ -1,9 +1,18 @@
 package runner
 
+import (
+	"strings"
+
+	"github.com/lamtuanvu/gh-runner-ctl/internal/config"
+	ghclient "github.com/lamtuanvu/gh-runner-ctl/internal/github"
+)
+
 // RunnerInfo merges Docker container info with optional GitHub runner status.
 type RunnerInfo struct {
 	Num          int
 	Name         string
+	Host         string // fleet host name; empty unless hosts are configured
+	Repo         string // "owner/name"; empty unless repos are configured
 	ContainerID  string
 	DockerState  string // running, exited, etc.
 	DockerStatus string // human-readable Docker status
@@ -12,4 +21,68 @@ type RunnerInfo struct {
 	GitHubID     int64
 	GitHubStatus string // online, offline
 	Busy         bool
+
+	// Labels are the custom labels last set with `ghr label`; Relabeled is
+	// false if they were never changed.
+	Labels    []string
+	Relabeled bool
+	// GitHubLabels are the custom labels GitHub reports for a relabeled
+	// runner when they differ from Labels, e.g. after a change in the GitHub
+	// UI; LabelsDrift is set when they do.
+	GitHubLabels []string
+	LabelsDrift  bool
+}
+
+// MergeGitHubStatus fills in the GitHub fields of each runner from the
+// GitHub runner registered under the same name. Runners GitHub does not
+// know are left as they are. Runners relabeled with `ghr label`, as set by
+// ApplyRunnerLabels, are checked against the custom labels GitHub reports.
+func MergeGitHubStatus(infos []RunnerInfo, statuses []ghclient.RunnerStatus) {
+	byName := make(map[string]ghclient.RunnerStatus, len(statuses))
+	for _, s := range statuses {
+		byName[s.Name] = s
+	}
+	for i := range infos {
+		if s, ok := byName[infos[i].Name]; ok {
+			infos[i].GitHubID = s.ID
+			infos[i].GitHubStatus = s.Status
+			infos[i].Busy = s.Busy
+			if infos[i].Relabeled && !sameLabels(infos[i].Labels, s.CustomLabels) {
+				infos[i].GitHubLabels = s.CustomLabels
+				infos[i].LabelsDrift = true
+			}
+		}
+	}
+}
+
+// ApplyRunnerLabels fills in the labels recorded by `ghr label` for each
+// runner. Records of other containers, such as an earlier container of the
+// same runner, are ignored.
+func ApplyRunnerLabels(infos []RunnerInfo, rl *config.RunnerLabels) {
+	for i := range infos {
+		if e, ok := rl.Runners[infos[i].ContainerID]; ok && e.Name == infos[i].Name {
+			infos[i].Labels = e.Labels
+			infos[i].Relabeled = true
+		}
+	}
+}
+
+// sameLabels reports whether a and b hold the same labels in any order.
+// GitHub compares labels case-insensitively.
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, l := range a {
+		seen[strings.ToLower(l)]++
+	}
+	for _, l := range b {
+		k := strings.ToLower(l)
+		if seen[k] == 0 {
+			return false
+		}
+		seen[k]--
+	}
+	return true
 }
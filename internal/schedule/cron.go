@@ -0,0 +1,204 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression (minute, hour, day of month,
+// month, day of week) evaluated in a fixed time zone.
+type Cron struct {
+	expr    string
+	loc     *time.Location
+	minute  [60]bool
+	hour    [24]bool
+	dom     [32]bool
+	month   [13]bool
+	dow     [7]bool
+	domStar bool
+	dowStar bool
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dowNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron parses a standard five-field cron expression. Fields accept
+// "*", numbers, ranges ("1-5"), lists ("1,3,5") and steps ("*/15", "8-18/2").
+// Months and weekdays also accept three-letter names, and 7 means Sunday.
+// The @hourly, @daily, @weekly, @monthly and @yearly macros are supported.
+func ParseCron(expr string, loc *time.Location) (*Cron, error) {
+	if loc == nil {
+		return nil, fmt.Errorf("cron %q: time zone is required", expr)
+	}
+	spec := strings.TrimSpace(expr)
+	if m, ok := macros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &Cron{expr: expr, loc: loc}
+	var dow [8]bool
+	steps := []struct {
+		name     string
+		field    string
+		set      []bool
+		min, max int
+		names    map[string]int
+	}{
+		{"minute", fields[0], c.minute[:], 0, 59, nil},
+		{"hour", fields[1], c.hour[:], 0, 23, nil},
+		{"day of month", fields[2], c.dom[:], 1, 31, nil},
+		{"month", fields[3], c.month[:], 1, 12, monthNames},
+		{"day of week", fields[4], dow[:], 0, 7, dowNames},
+	}
+	for _, s := range steps {
+		if err := parseField(s.field, s.set, s.min, s.max, s.names); err != nil {
+			return nil, fmt.Errorf("cron %q: %s: %w", expr, s.name, err)
+		}
+	}
+	copy(c.dow[:], dow[:7])
+	if dow[7] {
+		c.dow[0] = true
+	}
+	c.domStar = fields[2] == "*" || strings.HasPrefix(fields[2], "*/")
+	c.dowStar = fields[4] == "*" || strings.HasPrefix(fields[4], "*/")
+	return c, nil
+}
+
+func parseField(field string, set []bool, min, max int, names map[string]int) error {
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = parseValue(a, min, max, names); err != nil {
+				return err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseValue(b, min, max, names); err != nil {
+					return err
+				}
+			} else if hasStep {
+				hi = max
+			}
+			if hi < lo {
+				return fmt.Errorf("invalid range %q", rng)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+func parseValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+// String returns the original expression.
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Location returns the time zone the expression is evaluated in.
+func (c *Cron) Location() *time.Location {
+	return c.loc
+}
+
+// Next returns the first time strictly after t that matches the expression,
+// or the zero time if there is none within five years.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.In(c.loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, c.loc)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !c.month[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.loc)
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Prev returns the last time at or before t that matches the expression,
+// looking back at most about four years.
+func (c *Cron) Prev(t time.Time) (time.Time, bool) {
+	for _, back := range []time.Duration{8 * 24 * time.Hour, 32 * 24 * time.Hour, 4 * 366 * 24 * time.Hour} {
+		var last time.Time
+		for n := c.Next(t.Add(-back)); !n.IsZero() && !n.After(t); n = c.Next(n) {
+			last = n
+		}
+		if !last.IsZero() {
+			return last, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// dayMatches applies the usual cron rule: when both day of month and day of
+// week are restricted, a day matching either one matches.
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom[t.Day()]
+	dow := c.dow[t.Weekday()]
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
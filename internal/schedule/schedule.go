@@ -0,0 +1,58 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+)
+
+// Entry is a scheduled capacity change: from each time Cron fires, the
+// target runner count becomes Count until another entry fires.
+type Entry struct {
+	Name  string
+	Count int
+	Cron  *Cron
+}
+
+// Transition is a point in time at which an entry takes effect.
+type Transition struct {
+	At    time.Time
+	Entry Entry
+}
+
+// Current returns the most recent transition at or before t, i.e. the entry
+// that determines the target count at t. ok is false if no entry has fired.
+func Current(entries []Entry, t time.Time) (tr Transition, ok bool) {
+	for _, e := range entries {
+		at, found := e.Cron.Prev(t)
+		if !found {
+			continue
+		}
+		// Later entries win ties so the config order can break them.
+		if !ok || !at.Before(tr.At) {
+			tr, ok = Transition{At: at, Entry: e}, true
+		}
+	}
+	return tr, ok
+}
+
+// Upcoming returns the next n transitions strictly after t, ordered by time.
+func Upcoming(entries []Entry, t time.Time, n int) []Transition {
+	var all []Transition
+	for _, e := range entries {
+		at := t
+		for i := 0; i < n; i++ {
+			at = e.Cron.Next(at)
+			if at.IsZero() {
+				break
+			}
+			all = append(all, Transition{At: at, Entry: e})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].At.Before(all[j].At)
+	})
+	if len(all) > n {
+		all = all[:n]
+	}
+	return all
+}
@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseCron(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"*/15 8-18 * * 1-5",
+		"0 8 * * mon-fri",
+		"30 2 1,15 jan,jul *",
+		"0 0 * * 7",
+		"@daily",
+	}
+	for _, expr := range valid {
+		if _, err := ParseCron(expr, time.UTC); err != nil {
+			t.Errorf("ParseCron(%q) error = %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr, time.UTC); err == nil {
+			t.Errorf("ParseCron(%q) expected error", expr)
+		}
+	}
+
+	if _, err := ParseCron("* * * * *", nil); err == nil {
+		t.Error("ParseCron() expected error without a time zone")
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	loc := mustLoad(t, "Europe/Berlin")
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"0 8 * * 1-5", time.Date(2024, 3, 1, 9, 0, 0, 0, loc), time.Date(2024, 3, 4, 8, 0, 0, 0, loc)}, // Fri -> Mon
+		{"0 8 * * 1-5", time.Date(2024, 3, 4, 7, 59, 30, 0, loc), time.Date(2024, 3, 4, 8, 0, 0, 0, loc)},
+		{"*/15 * * * *", time.Date(2024, 3, 4, 10, 15, 0, 0, loc), time.Date(2024, 3, 4, 10, 30, 0, 0, loc)},
+		{"0 0 1 * *", time.Date(2024, 1, 31, 12, 0, 0, 0, loc), time.Date(2024, 2, 1, 0, 0, 0, 0, loc)},
+		{"0 0 29 2 *", time.Date(2024, 3, 1, 0, 0, 0, 0, loc), time.Date(2028, 2, 29, 0, 0, 0, 0, loc)},
+		// Both day fields restricted: the 13th OR a Friday.
+		{"0 0 13 * 5", time.Date(2024, 9, 1, 0, 0, 0, 0, loc), time.Date(2024, 9, 6, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr, loc)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+		}
+		if got := c.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%v) = %v, want %v", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestCronTimeZone(t *testing.T) {
+	tokyo := mustLoad(t, "Asia/Tokyo")
+	c, err := ParseCron("0 8 * * *", tokyo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := c.Next(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	want := time.Date(2024, 3, 4, 23, 0, 0, 0, time.UTC) // 08:00 JST next day
+	if !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestCurrentAndUpcoming(t *testing.T) {
+	loc := mustLoad(t, "Europe/Berlin")
+	parse := func(expr string) *Cron {
+		c, err := ParseCron(expr, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	entries := []Entry{
+		{Name: "workday", Count: 20, Cron: parse("0 8 * * 1-5")},
+		{Name: "night", Count: 2, Cron: parse("0 19 * * 1-5")},
+	}
+
+	// Saturday noon: Friday 19:00 "night" is the latest transition.
+	sat := time.Date(2024, 3, 9, 12, 0, 0, 0, loc)
+	tr, ok := Current(entries, sat)
+	if !ok || tr.Entry.Name != "night" || !tr.At.Equal(time.Date(2024, 3, 8, 19, 0, 0, 0, loc)) {
+		t.Errorf("Current(sat) = %+v, %v", tr, ok)
+	}
+
+	// Tuesday 08:00 exactly: workday takes effect.
+	tue := time.Date(2024, 3, 5, 8, 0, 0, 0, loc)
+	if tr, _ := Current(entries, tue); tr.Entry.Name != "workday" {
+		t.Errorf("Current(tue 08:00) = %s, want workday", tr.Entry.Name)
+	}
+
+	up := Upcoming(entries, sat, 3)
+	want := []struct {
+		name string
+		at   time.Time
+	}{
+		{"workday", time.Date(2024, 3, 11, 8, 0, 0, 0, loc)},
+		{"night", time.Date(2024, 3, 11, 19, 0, 0, 0, loc)},
+		{"workday", time.Date(2024, 3, 12, 8, 0, 0, 0, loc)},
+	}
+	if len(up) != len(want) {
+		t.Fatalf("Upcoming() returned %d transitions, want %d", len(up), len(want))
+	}
+	for i, w := range want {
+		if up[i].Entry.Name != w.name || !up[i].At.Equal(w.at) {
+			t.Errorf("Upcoming()[%d] = %s at %v, want %s at %v", i, up[i].Entry.Name, up[i].At, w.name, w.at)
+		}
+	}
+}